@@ -2,4 +2,4 @@ module Agent
 
 go 1.25.5
 
-require github.com/gorilla/websocket v1.5.3 // indirect
+require github.com/gorilla/websocket v1.5.3
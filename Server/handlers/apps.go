@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"Agent/utils"
+)
+
+// RunningAppsHandler: GET /api/apps
+func RunningAppsHandler(w http.ResponseWriter, r *http.Request) {
+	apps, err := utils.ListRunningApps()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(apps)
+}
+
+// AppActionHandler: POST /api/apps/{bundle}/{action}, action = activate|hide|unhide|quit|force-quit
+func AppActionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	res, err := utils.AppAction(r.PathValue("bundle"), r.PathValue("action"))
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, utils.ErrAppNotRunning) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// InstalledAppsHandler: GET /api/apps/installed
+func InstalledAppsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.GetInstalledApps())
+}
+
+// AppIconHandler: GET /api/apps/icon?bundle_id=com.apple.Safari&size=128 (PNG)
+func AppIconHandler(w http.ResponseWriter, r *http.Request) {
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	if size == 0 {
+		size = 128
+	}
+
+	path, err := utils.AppIcon(r.URL.Query().Get("bundle_id"), size)
+	switch {
+	case errors.Is(err, utils.ErrInvalidValue):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, utils.ErrUnsupported):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "max-age=86400")
+	http.ServeFile(w, r, path)
+}
+
+// LaunchAppHandler: POST /api/apps/launch
+// {"bundle_id":"com.google.Chrome","urls":["https://example.com"],"new_instance":true,"args":["--incognito"]}
+func LaunchAppHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Hanya JSON: body text/plain bisa dikirim lintas origin tanpa preflight
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var req utils.LaunchOptions
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res, err := utils.LaunchApp(req)
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"Agent/utils"
+)
+
+// AudioHandler: GET state volume, POST {"output_volume":40,"muted":false} lalu kirim state terbaru
+func AudioHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		state utils.AudioState
+		err   error
+	)
+
+	switch r.Method {
+	case http.MethodGet:
+		state, err = utils.GetAudioState()
+	case http.MethodPost:
+		var req utils.AudioUpdate
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		state, err = utils.SetAudio(req)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+type AudioDeviceRequest struct {
+	Type   string `json:"type"`   // output, input, system
+	Device string `json:"device"` // nama atau UID
+}
+
+// AudioDevicesHandler: GET /api/audio/devices
+func AudioDevicesHandler(w http.ResponseWriter, r *http.Request) {
+	devices, err := utils.ListAudioDevices()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(devices)
+}
+
+// AudioDefaultDeviceHandler: POST /api/audio/devices/default {"type":"output","device":"AirPods Pro"}
+func AudioDefaultDeviceHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req AudioDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := utils.SetDefaultAudioDevice(req.Type, req.Device)
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	AudioDevicesHandler(w, r)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"Agent/utils"
+)
+
+type BrightnessRequest struct {
+	Display string `json:"display"` // ID dari GET /api/brightness
+	Value   *int   `json:"value"`   // 0-100, wajib (kosong jangan sampai jadi 0 = layar gelap)
+}
+
+// BrightnessHandler: GET brightness semua display, POST {"display":"builtin:0","value":70}
+func BrightnessHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(utils.GetDisplays())
+
+	case http.MethodPost:
+		var req BrightnessRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Value == nil {
+			http.Error(w, "value is required", http.StatusBadRequest)
+			return
+		}
+
+		display, err := utils.SetBrightness(req.Display, *req.Value)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case errors.Is(err, utils.ErrUnsupported):
+			w.WriteHeader(http.StatusNotImplemented)
+			json.NewEncoder(w).Encode(map[string]string{"status": "unsupported", "error": err.Error()})
+		case errors.Is(err, utils.ErrInvalidValue):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			json.NewEncoder(w).Encode(display)
+		}
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"Agent/utils"
+)
+
+// Batas upload clipboard (gambar dari HP)
+const maxClipboardBody = 20 << 20
+
+// ClipboardHandler:
+//
+//	GET /api/clipboard              -> JSON (teks + tipe yang tersedia)
+//	GET /api/clipboard?format=rtf   -> application/rtf
+//	GET /api/clipboard?format=png   -> image/png
+//	PUT /api/clipboard              -> isi dari body, tipe dari Content-Type (text/plain, text/rtf, image/png)
+func ClipboardHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		getClipboard(w, r)
+	case http.MethodPut:
+		putClipboard(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func getClipboard(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Query().Get("format") {
+	case "rtf":
+		data, err := utils.GetClipboardRTF()
+		writeClipboardData(w, "application/rtf", data, err)
+	case "png":
+		data, err := utils.GetClipboardPNG()
+		writeClipboardData(w, "image/png", data, err)
+	default:
+		c, err := utils.GetClipboard()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(c)
+	}
+}
+
+func writeClipboardData(w http.ResponseWriter, contentType string, data []byte, err error) {
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
+
+func putClipboard(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxClipboardBody))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	kind := "text"
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/rtf", "application/rtf":
+		kind = "rtf"
+	case "image/png":
+		kind = "png"
+	}
+
+	err = utils.SetClipboard(kind, data)
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// ClipboardHistoryHandler: GET /api/clipboard/history, terbaru di depan
+func ClipboardHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.GetClipboardHistory())
+}
+
+// ClipboardSettingsHandler: GET/PUT /api/clipboard/settings
+func ClipboardSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(utils.GetClipboardSettings())
+
+	case http.MethodPut:
+		req := utils.GetClipboardSettings()
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		settings, err := utils.SetClipboardSettings(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(settings)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"Agent/utils" // PERHATIKAN: Gunakan "Agent/utils", bukan "go-agent/utils"
 	"encoding/json"
+	"errors"
 	"net/http"
 )
 
@@ -41,6 +42,10 @@ func HandleControl(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		// Log error ke terminal server agar terlihat jika ada masalah script
 		println("Error executing command:", err.Error())
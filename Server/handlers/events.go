@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"Agent/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+// eventTopics: topic dari ?topic=, topic pribadi (clipboard) wajib token agent
+func eventTopics(r *http.Request) ([]string, bool) {
+	var topics []string
+	if t := r.URL.Query().Get("topic"); t != "" {
+		topics = strings.Split(t, ",")
+	}
+	for _, t := range topics {
+		if utils.IsPrivateTopic(t) && !utils.CheckToken(r) {
+			return nil, false
+		}
+	}
+	return topics, true
+}
+
+// EventsHandler: stream SSE, GET /events?topic=process,media (kosong = semua kecuali clipboard)
+func EventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	topics, ok := eventTopics(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	events, cancel := utils.SubscribeEvents(topics...)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	// Komentar berkala supaya koneksi tidak diputus proxy/idle timeout
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			data, _ := json.Marshal(ev)
+			fmt.Fprintf(w, "event: %s.%s\ndata: %s\n\n", ev.Topic, ev.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+var upgrader = websocket.Upgrader{
+	// Client (app MacMon) berjalan di origin berbeda, sama seperti CORS "*"
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// EventsWSHandler: sama seperti EventsHandler tapi lewat WebSocket, satu pesan JSON per event
+func EventsWSHandler(w http.ResponseWriter, r *http.Request) {
+	topics, ok := eventTopics(r)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	events, cancel := utils.SubscribeEvents(topics...)
+	defer cancel()
+
+	// Baca sampai client menutup koneksi (pesan dari client diabaikan)
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(ev); err != nil {
+				return
+			}
+		}
+	}
+}
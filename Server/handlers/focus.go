@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"Agent/utils"
+)
+
+// FocusHandler: GET state Focus, POST {"mode":"Work","enabled":true,"expires_in":1500}
+func FocusHandler(w http.ResponseWriter, r *http.Request) {
+	var (
+		state utils.FocusState
+		err   error
+	)
+
+	switch r.Method {
+	case http.MethodGet:
+		state, err = utils.GetFocusState()
+
+	case http.MethodPost:
+		var req utils.FocusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		state, err = utils.SetFocus(req)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case errors.Is(err, utils.ErrUnsupported):
+		w.WriteHeader(http.StatusNotImplemented)
+		json.NewEncoder(w).Encode(map[string]string{"status": "unsupported", "error": err.Error()})
+	case errors.Is(err, utils.ErrInvalidValue):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		json.NewEncoder(w).Encode(state)
+	}
+}
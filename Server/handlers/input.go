@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"Agent/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+// Interval pengiriman gerakan mouse yang sudah digabung (~120 Hz)
+const inputFlushInterval = 8 * time.Millisecond
+
+// inputUpgrader: hanya client native (tanpa Origin) atau halaman dari agent sendiri.
+// Halaman web lain tidak boleh bisa mengetik ke Mac.
+var inputUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		return err == nil && u.Host == r.Host
+	},
+}
+
+// InputWSHandler: WebSocket /api/input/ws, satu InputEvent JSON per pesan.
+// Error dikirim balik sebagai {"error": "..."} tanpa menutup koneksi.
+func InputWSHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := inputUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	session := utils.NewInputSession()
+	defer session.Close()
+
+	events := make(chan utils.InputEvent, 256)
+	go func() {
+		defer close(events)
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var ev utils.InputEvent
+			if err := json.Unmarshal(msg, &ev); err != nil {
+				// Pesan rusak cukup dilewati, koneksi tetap dipakai
+				ev = utils.InputEvent{Type: "invalid"}
+			}
+			events <- ev
+		}
+	}()
+
+	ticker := time.NewTicker(inputFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := session.Handle(ev); err != nil {
+				conn.WriteJSON(map[string]string{"error": err.Error()})
+			}
+		case <-ticker.C:
+			if err := session.Flush(); err != nil {
+				conn.WriteJSON(map[string]string{"error": err.Error()})
+			}
+		}
+	}
+}
+
+// InputHandler: POST /api/input, satu event tanpa WebSocket (mis. shortcut dari widget)
+func InputHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var ev utils.InputEvent
+	if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session := utils.NewInputSession()
+	err := session.Handle(ev)
+	if err == nil {
+		err = session.Flush()
+	}
+	session.Close()
+
+	switch {
+	case errors.Is(err, utils.ErrInvalidValue):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, utils.ErrUnsupported):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+	}
+}
@@ -7,9 +7,45 @@ import (
 )
 
 func MediaInfoHandler(w http.ResponseWriter, r *http.Request) {
-	// Ambil data terbaru dari Mac
-	info := utils.GetMediaInfo()
+	// Dari cache media watcher, tidak menjalankan osascript setiap request
+	info := utils.GetCachedMediaInfo()
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(info)
 }
+
+// MediaArtworkHandler: GET /api/media/artwork?id=..., gambar cover track yang sedang diputar
+func MediaArtworkHandler(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	data, mime, ok := utils.GetArtwork(id)
+	if !ok {
+		http.Error(w, "No artwork", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", mime)
+	if id != "" {
+		// ?id= berubah setiap artwork berubah dan sudah dicocokkan, jadi aman di-cache lama
+		w.Header().Set("Cache-Control", "max-age=86400")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Write(data)
+}
+
+// MediaCommandHandler: POST /api/media/command {"player":"Spotify","action":"seek","value":90}
+func MediaCommandHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req utils.MediaCommand
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state, err := utils.SendMediaCommand(req)
+	writeMediaResult(w, state, err)
+}
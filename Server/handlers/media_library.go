@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"Agent/utils"
+)
+
+// PlaylistsHandler: GET /api/media/playlists?player=Music
+func PlaylistsHandler(w http.ResponseWriter, r *http.Request) {
+	playlists, err := utils.ListPlaylists(r.URL.Query().Get("player"))
+	writeMediaResult(w, playlists, err)
+}
+
+// LibrarySearchHandler: GET /api/media/search?q=beatles&type=songs|albums|artists
+func LibrarySearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	items, err := utils.SearchLibrary(q.Get("player"), q.Get("q"), q.Get("type"))
+	writeMediaResult(w, items, err)
+}
+
+// LibraryPlayHandler: POST /api/media/play {"playlist_id":"..."} / {"track_id":"..."} / {"uri":"spotify:..."}
+func LibraryPlayHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req utils.PlayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state, err := utils.PlayFromLibrary(req)
+	writeMediaResult(w, state, err)
+}
+
+// UpNextHandler: GET /api/media/queue, saat ini tidak ada player yang mengizinkan
+func UpNextHandler(w http.ResponseWriter, r *http.Request) {
+	err := utils.GetUpNext(r.URL.Query().Get("player"))
+	writeMediaResult(w, nil, err)
+}
+
+func writeMediaResult(w http.ResponseWriter, result any, err error) {
+	switch {
+	case errors.Is(err, utils.ErrInvalidValue):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, utils.ErrUnsupported):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotImplemented)
+		json.NewEncoder(w).Encode(map[string]string{"status": "unsupported", "error": err.Error()})
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
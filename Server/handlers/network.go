@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"Agent/utils"
+)
+
+// ProcessNetworkHandler: GET /network/processes?limit=20, diurutkan dari bandwidth terbesar
+func ProcessNetworkHandler(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.GetProcessNetwork(limit))
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Agent/utils"
+)
+
+// SuspendProcessHandler: POST /processes/{pid}/suspend?resume_after=600&override=true
+func SuspendProcessHandler(w http.ResponseWriter, r *http.Request) {
+	pid, ok := pidAction(w, r)
+	if !ok {
+		return
+	}
+
+	entry, err := utils.SuspendProcess(pid, resumeAfter(r), r.URL.Query().Get("override") == "true")
+	if err != nil {
+		writeActionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// ResumeProcessHandler: POST /processes/{pid}/resume
+func ResumeProcessHandler(w http.ResponseWriter, r *http.Request) {
+	pid, ok := pidAction(w, r)
+	if !ok {
+		return
+	}
+
+	if err := utils.ResumeProcess(pid); err != nil {
+		writeActionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// ReniceProcessHandler: POST /processes/{pid}/nice?value=10
+func ReniceProcessHandler(w http.ResponseWriter, r *http.Request) {
+	pid, ok := pidAction(w, r)
+	if !ok {
+		return
+	}
+
+	nice, err := strconv.Atoi(r.URL.Query().Get("value"))
+	if err != nil {
+		http.Error(w, "Invalid nice value", http.StatusBadRequest)
+		return
+	}
+
+	if err := utils.ReniceProcess(pid, nice, r.URL.Query().Get("override") == "true"); err != nil {
+		writeActionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// NotifyOnExitHandler: POST /processes/{pid}/notify, notifikasi macOS saat proses selesai
+func NotifyOnExitHandler(w http.ResponseWriter, r *http.Request) {
+	pid, ok := pidAction(w, r)
+	if !ok {
+		return
+	}
+
+	if err := utils.NotifyOnExit(pid); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+}
+
+// SuspendedListHandler: GET /processes/suspended
+func SuspendedListHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.ListSuspended())
+}
+
+// AppSuspendHandler: POST /processes/apps/suspend?name=Xcode&resume_after=600
+func AppSuspendHandler(w http.ResponseWriter, r *http.Request) {
+	appAction(w, r, func(name string) ([]utils.ProcessActionResult, error) {
+		return utils.SuspendAppGroup(name, resumeAfter(r), r.URL.Query().Get("override") == "true")
+	})
+}
+
+// AppResumeHandler: POST /processes/apps/resume?name=Xcode
+func AppResumeHandler(w http.ResponseWriter, r *http.Request) {
+	appAction(w, r, utils.ResumeAppGroup)
+}
+
+// AppReniceHandler: POST /processes/apps/nice?name=Xcode&value=10
+func AppReniceHandler(w http.ResponseWriter, r *http.Request) {
+	nice, err := strconv.Atoi(r.URL.Query().Get("value"))
+	if err != nil {
+		http.Error(w, "Invalid nice value", http.StatusBadRequest)
+		return
+	}
+
+	appAction(w, r, func(name string) ([]utils.ProcessActionResult, error) {
+		return utils.ReniceAppGroup(name, nice, r.URL.Query().Get("override") == "true")
+	})
+}
+
+func pidAction(w http.ResponseWriter, r *http.Request) (int, bool) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(r.PathValue("pid"))
+	if err != nil {
+		http.Error(w, "Invalid PID", http.StatusBadRequest)
+		return 0, false
+	}
+	return pid, true
+}
+
+func appAction(w http.ResponseWriter, r *http.Request, action func(string) ([]utils.ProcessActionResult, error)) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	results, err := action(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// resumeAfter: ?resume_after dalam detik, 0 = default utils.DefaultResumeAfter
+func resumeAfter(r *http.Request) time.Duration {
+	secs, _ := strconv.Atoi(r.URL.Query().Get("resume_after"))
+	return time.Duration(secs) * time.Second
+}
+
+// writeActionError: ProtectionError dikirim sebagai JSON 403, sisanya 500
+func writeActionError(w http.ResponseWriter, err error) {
+	var perr *utils.ProtectionError
+	if errors.As(err, &perr) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(perr)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -5,15 +5,74 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"Agent/utils"
 )
 
+// ListProcessesHandler: tanpa query = array top 50 (format lama),
+// dengan query (?sort=mem&order=desc&category=User&filter=chrome&limit=20&offset=20) = ProcessPage
 func ListProcessesHandler(w http.ResponseWriter, r *http.Request) {
-	processes := utils.GetTopProcesses(50)
-
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(processes)
+
+	q := r.URL.Query()
+	if len(q) == 0 {
+		json.NewEncoder(w).Encode(utils.GetTopProcesses(utils.DefaultProcessLimit))
+		return
+	}
+
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	offset, _ := strconv.Atoi(q.Get("offset"))
+
+	page, err := utils.QueryProcesses(utils.ProcessQuery{
+		Sort:     q.Get("sort"),
+		Order:    q.Get("order"),
+		Category: q.Get("category"),
+		Filter:   q.Get("filter"),
+		Regex:    q.Get("regex"),
+		User:     q.Get("user"),
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+type KillRequest struct {
+	PIDs     []int   `json:"pids"`
+	Signal   string  `json:"signal"`
+	Escalate bool    `json:"escalate"`
+	Grace    float64 `json:"grace"` // detik
+	Override bool    `json:"override"`
+}
+
+// parseKillOptions: ?signal=TERM&escalate=true&grace=5&override=true
+func parseKillOptions(r *http.Request) (utils.KillOptions, error) {
+	q := r.URL.Query()
+	grace, _ := strconv.ParseFloat(q.Get("grace"), 64)
+	return buildKillOptions(KillRequest{
+		Signal:   q.Get("signal"),
+		Escalate: q.Get("escalate") == "true",
+		Grace:    grace,
+		Override: q.Get("override") == "true",
+	})
+}
+
+func buildKillOptions(req KillRequest) (utils.KillOptions, error) {
+	sig, err := utils.ParseSignal(req.Signal)
+	if err != nil {
+		return utils.KillOptions{}, err
+	}
+	return utils.KillOptions{
+		Signal:   sig,
+		Escalate: req.Escalate,
+		Grace:    time.Duration(req.Grace * float64(time.Second)),
+		Override: req.Override,
+	}, nil
 }
 
 func KillProcessHandler(w http.ResponseWriter, r *http.Request) {
@@ -34,12 +93,156 @@ func KillProcessHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = utils.KillProcess(pid)
+	opts, err := parseKillOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := utils.KillWithOptions(pid, opts)
+	w.Header().Set("Content-Type", "application/json")
+	if result.Code != "" {
+		// Ditolak policy: kirim JSON supaya client bisa menawarkan override
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+	if result.Error != "" {
+		http.Error(w, fmt.Sprintf("Failed to kill process: %s", result.Error), http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(result)
+}
+
+// KillByNameHandler: POST /kill/name?name=node&signal=TERM
+func KillByNameHandler(w http.ResponseWriter, r *http.Request) {
+	killGroupHandler(w, r, utils.KillByName)
+}
+
+// KillAppHandler: POST /kill/app?name=Google Chrome&escalate=true
+func KillAppHandler(w http.ResponseWriter, r *http.Request) {
+	killGroupHandler(w, r, utils.KillAppGroup)
+}
+
+func killGroupHandler(w http.ResponseWriter, r *http.Request, kill func(string, utils.KillOptions) ([]utils.KillResult, error)) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := parseKillOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := kill(name, opts)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to kill process: %v", err), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf("Process %d killed successfully", pid)))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// KillPolicyHandler: policy proteksi yang sedang aktif
+func KillPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.GetProtectionPolicy())
+}
+
+// KillBatchHandler: POST /kill/batch {"pids":[1,2],"signal":"TERM","escalate":true,"grace":5}
+func KillBatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req KillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.PIDs) == 0 {
+		http.Error(w, "PIDs are required", http.StatusBadRequest)
+		return
+	}
+
+	opts, err := buildKillOptions(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.KillPIDs(req.PIDs, opts))
+}
+
+// ClassifierRulesHandler: rule klasifikasi aktif, urut sesuai prioritas
+func ClassifierRulesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.GetClassifierRules())
+}
+
+// ProcessTreeHandler: hierarki parent/child semua proses
+func ProcessTreeHandler(w http.ResponseWriter, r *http.Request) {
+	tree := utils.BuildProcessTree()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
+// AppGroupsHandler: proses dikelompokkan per aplikasi (?name=Chrome untuk satu grup)
+func AppGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		json.NewEncoder(w).Encode(utils.GetAppGroups())
+		return
+	}
+
+	group, ok := utils.FindAppGroup(name)
+	if !ok {
+		http.Error(w, "App not found", http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(group)
+}
+
+// ProcessHistoryHandler: GET /processes/{pid}/history
+func ProcessHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.PathValue("pid"))
+	if err != nil {
+		http.Error(w, "Invalid PID", http.StatusBadRequest)
+		return
+	}
+
+	history, ok := utils.GetProcessHistory(pid)
+	if !ok {
+		http.Error(w, "No history for this PID", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// TopMoversHandler: GET /processes/movers?by=cpu|mem&limit=10
+func TopMoversHandler(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 10
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(utils.GetTopMovers(r.URL.Query().Get("by"), limit))
 }
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Agent/utils"
+)
+
+const (
+	maxStreamFPS     = 5.0
+	defaultStreamFPS = 1.0
+	streamBoundary   = "agentframe"
+)
+
+func screenshotOptions(r *http.Request) utils.ScreenshotOptions {
+	q := r.URL.Query()
+	display, _ := strconv.Atoi(q.Get("display"))
+	window, _ := strconv.Atoi(q.Get("window"))
+	width, _ := strconv.Atoi(q.Get("width"))
+	quality, _ := strconv.Atoi(q.Get("quality"))
+	return utils.ScreenshotOptions{Display: display, Window: window, Width: width, Quality: quality}
+}
+
+// ScreenshotHandler: GET /api/screen/screenshot?display=1&window=123&width=1280&quality=70 (JPEG)
+func ScreenshotHandler(w http.ResponseWriter, r *http.Request) {
+	img, err := utils.CaptureScreen(screenshotOptions(r))
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(img)
+}
+
+// WindowsHandler: GET /api/screen/windows
+func WindowsHandler(w http.ResponseWriter, r *http.Request) {
+	windows, err := utils.ListWindows()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(windows)
+}
+
+// ScreenStreamHandler: GET /api/screen/stream?fps=1&width=1280&quality=70 (MJPEG).
+// Kualitas dan resolusi menyesuaikan kecepatan client menerima frame.
+func ScreenStreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	fps, err := strconv.ParseFloat(r.URL.Query().Get("fps"), 64)
+	if err != nil || fps <= 0 {
+		fps = defaultStreamFPS
+	}
+	fps = min(fps, maxStreamFPS)
+	interval := time.Duration(float64(time.Second) / fps)
+
+	opts := screenshotOptions(r)
+	if opts.Width == 0 {
+		opts.Width = 1280
+	}
+	if opts.Quality == 0 {
+		opts.Quality = utils.DefaultJPEGQuality
+	}
+
+	// Frame pertama sekaligus validasi parameter sebelum header stream dikirim
+	img, err := utils.CaptureScreen(opts)
+	if errors.Is(err, utils.ErrInvalidValue) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+streamBoundary)
+	w.Header().Set("Cache-Control", "no-store")
+
+	adaptive := utils.NewAdaptiveQuality(opts.Quality, opts.Width)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", streamBoundary, len(img)); err != nil {
+			return
+		}
+		if _, err := w.Write(append(img, "\r\n"...)); err != nil {
+			return
+		}
+		flusher.Flush()
+		adaptive.Adjust(time.Since(start), interval)
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		opts.Quality, opts.Width = adaptive.Quality, adaptive.Width
+		if img, err = utils.CaptureScreen(opts); err != nil {
+			return
+		}
+	}
+}
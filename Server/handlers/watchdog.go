@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Agent/utils"
+)
+
+type WatchdogRequest struct {
+	Name     string `json:"name"`
+	BundleID string `json:"bundle_id"`
+}
+
+// WatchdogHandler: GET daftar app, POST daftarkan app, DELETE ?id= hapus
+func WatchdogHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(utils.ListWatchdog())
+
+	case http.MethodPost:
+		var req WatchdogRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		app, err := utils.RegisterWatchdog(req.Name, req.BundleID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(app)
+
+	case http.MethodDelete:
+		if err := utils.UnregisterWatchdog(r.URL.Query().Get("id")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"status": "success"})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
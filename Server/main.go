@@ -5,18 +5,82 @@ import (
 	"Agent/utils"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
 	utils.StartMetricsCollector()
+	utils.StartProcessSampler()
+	utils.StartNetworkCollector()
+	utils.StartProcessWatcher()
+	utils.StartAudioDeviceWatcher()
+	utils.StartMediaWatcher()
+	utils.StartClipboardWatcher()
+
+	tokenFile := envOr("AGENT_TOKEN_FILE", "agent.token")
+	if _, err := utils.LoadAgentToken(tokenFile); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Token untuk input/clipboard/layar: AGENT_TOKEN atau %s", tokenFile)
+
+	// Proses yang di-suspend tidak boleh tetap beku setelah agent berhenti
+	if err := utils.LoadSuspended(envOr("AGENT_SUSPENDED_FILE", "suspended.json")); err != nil {
+		log.Fatal(err)
+	}
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		<-sig
+		utils.ResumeAllSuspended()
+		os.Exit(0)
+	}()
+
+	if err := utils.LoadProtectionPolicy(envOr("AGENT_PROTECTION_FILE", "protection.json")); err != nil {
+		log.Fatal(err)
+	}
+	if err := utils.LoadClassifierRules(envOr("AGENT_CLASSIFIER_FILE", "classifier.json")); err != nil {
+		log.Fatal(err)
+	}
+	if err := utils.LoadFocusShortcuts(envOr("AGENT_FOCUS_FILE", "focus.json")); err != nil {
+		log.Fatal(err)
+	}
+	if err := utils.StartWatchdog(envOr("AGENT_WATCHDOG_FILE", "watchdog.json")); err != nil {
+		log.Fatal(err)
+	}
 
 	// --- Monitoring ---
 	http.HandleFunc("/stats", handlers.StatsHandler)
 	http.HandleFunc("/stats-json", handlers.StatsOnceHandler)
 
+	// --- Events (SSE & WebSocket) ---
+	http.HandleFunc("/events", enableCors(handlers.EventsHandler))
+	http.HandleFunc("/events/ws", handlers.EventsWSHandler)
+
+	// --- Network ---
+	http.HandleFunc("/network/processes", enableCors(handlers.ProcessNetworkHandler))
+
 	// --- Processes ---
 	http.HandleFunc("/processes", enableCors(handlers.ListProcessesHandler))
+	http.HandleFunc("/processes/tree", enableCors(handlers.ProcessTreeHandler))
+	http.HandleFunc("/processes/apps", enableCors(handlers.AppGroupsHandler))
+	http.HandleFunc("/processes/rules", enableCors(handlers.ClassifierRulesHandler))
+	http.HandleFunc("/processes/movers", enableCors(handlers.TopMoversHandler))
+	http.HandleFunc("/processes/{pid}/history", enableCors(handlers.ProcessHistoryHandler))
+	http.HandleFunc("/processes/{pid}/suspend", enableCors(handlers.SuspendProcessHandler))
+	http.HandleFunc("/processes/{pid}/resume", enableCors(handlers.ResumeProcessHandler))
+	http.HandleFunc("/processes/{pid}/nice", enableCors(handlers.ReniceProcessHandler))
+	http.HandleFunc("/processes/{pid}/notify", enableCors(handlers.NotifyOnExitHandler))
+	http.HandleFunc("/processes/suspended", enableCors(handlers.SuspendedListHandler))
+	http.HandleFunc("/processes/apps/suspend", enableCors(handlers.AppSuspendHandler))
+	http.HandleFunc("/processes/apps/resume", enableCors(handlers.AppResumeHandler))
+	http.HandleFunc("/processes/apps/nice", enableCors(handlers.AppReniceHandler))
 	http.HandleFunc("/kill", enableCors(handlers.KillProcessHandler))
+	http.HandleFunc("/kill/name", enableCors(handlers.KillByNameHandler))
+	http.HandleFunc("/kill/app", enableCors(handlers.KillAppHandler))
+	http.HandleFunc("/kill/batch", enableCors(handlers.KillBatchHandler))
+	http.HandleFunc("/kill/policy", enableCors(handlers.KillPolicyHandler))
 
 	// --- Power Control ---
 	http.HandleFunc("/api/action/restart", enableCors(handlers.RestartSystem))
@@ -27,18 +91,79 @@ func main() {
 	// Pastikan handler HandleControl sudah Anda buat sebelumnya di handlers/control.go
 	// Jika belum, gunakan kode dari diskusi sebelumnya.
 	http.HandleFunc("/api/control", enableCors(handlers.HandleControl))
+	http.HandleFunc("/api/audio", enableCors(handlers.AudioHandler))
+	http.HandleFunc("/api/audio/devices", enableCors(handlers.AudioDevicesHandler))
+	http.HandleFunc("/api/audio/devices/default", enableCors(handlers.AudioDefaultDeviceHandler))
+	http.HandleFunc("/api/brightness", enableCors(handlers.BrightnessHandler))
+
+	// --- Remote Keyboard & Trackpad ---
+	// Tanpa CORS dan wajib token: input bisa mengetik ke Terminal
+	http.HandleFunc("/api/input", requireToken(handlers.InputHandler))
+	http.HandleFunc("/api/input/ws", requireToken(handlers.InputWSHandler))
+
+	// --- Clipboard ---
+	// Tanpa CORS dan wajib token: isi clipboard tidak boleh terbaca dari website lain
+	http.HandleFunc("/api/clipboard", requireToken(handlers.ClipboardHandler))
+	http.HandleFunc("/api/clipboard/history", requireToken(handlers.ClipboardHistoryHandler))
+	http.HandleFunc("/api/clipboard/settings", requireToken(handlers.ClipboardSettingsHandler))
+
+	// --- Screen ---
+	// Tanpa CORS dan wajib token: isi layar tidak boleh terbaca dari website lain
+	http.HandleFunc("/api/screen/screenshot", requireToken(handlers.ScreenshotHandler))
+	http.HandleFunc("/api/screen/windows", requireToken(handlers.WindowsHandler))
+	http.HandleFunc("/api/screen/stream", requireToken(handlers.ScreenStreamHandler))
+
+	// --- Focus / Do Not Disturb ---
+	http.HandleFunc("/api/focus", enableCors(handlers.FocusHandler))
+
+	// --- Running Apps ---
+	http.HandleFunc("/api/apps", enableCors(handlers.RunningAppsHandler))
+	http.HandleFunc("/api/apps/installed", enableCors(handlers.InstalledAppsHandler))
+	http.HandleFunc("/api/apps/icon", enableCors(handlers.AppIconHandler))
+	// Tanpa CORS dan wajib token: bisa menjalankan app apa pun dengan argumen bebas
+	http.HandleFunc("/api/apps/launch", requireToken(handlers.LaunchAppHandler))
+	http.HandleFunc("/api/apps/{bundle}/{action}", enableCors(handlers.AppActionHandler))
+
+	// --- Watchdog (relaunch app yang mati) ---
+	http.HandleFunc("/api/watchdog", enableCors(handlers.WatchdogHandler))
 
 	// --- MEDIA INFO (BARU) ---
 	http.HandleFunc("/api/media/info", enableCors(handlers.MediaInfoHandler))
+	http.HandleFunc("/api/media/artwork", enableCors(handlers.MediaArtworkHandler))
+	http.HandleFunc("/api/media/command", enableCors(handlers.MediaCommandHandler))
+	http.HandleFunc("/api/media/playlists", enableCors(handlers.PlaylistsHandler))
+	http.HandleFunc("/api/media/search", enableCors(handlers.LibrarySearchHandler))
+	http.HandleFunc("/api/media/play", enableCors(handlers.LibraryPlayHandler))
+	http.HandleFunc("/api/media/queue", enableCors(handlers.UpNextHandler))
 
 	log.Println("Mac Monitor Agent running on :8080")
-	log.Fatal(http.ListenAndServe("0.0.0.0:8080", nil))
+	err := http.ListenAndServe("0.0.0.0:8080", nil)
+	utils.ResumeAllSuspended()
+	log.Fatal(err)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// requireToken: endpoint sensitif hanya untuk client yang memegang token agent
+func requireToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !utils.CheckToken(r) {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
 }
 
 func enableCors(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
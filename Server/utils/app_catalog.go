@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type InstalledApp struct {
+	Name     string `json:"name"`
+	BundleID string `json:"bundle_id"`
+	Version  string `json:"version,omitempty"`
+	Path     string `json:"path"`
+
+	iconFile string
+}
+
+var (
+	catalog     []InstalledApp
+	catalogSig  string
+	catalogLock sync.Mutex
+)
+
+func appDirs() []string {
+	home, _ := os.UserHomeDir()
+	return []string{
+		"/Applications",
+		"/Applications/Utilities",
+		filepath.Join(home, "Applications"),
+		"/System/Applications",
+		"/System/Applications/Utilities",
+	}
+}
+
+// dirSignature: mtime folder berubah saat app ditambah/dihapus, jadi cukup ini untuk cek perubahan
+func dirSignature(dirs []string) string {
+	var sb strings.Builder
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil {
+			fmt.Fprintf(&sb, "%s:%d;", dir, info.ModTime().UnixNano())
+		}
+	}
+	return sb.String()
+}
+
+// GetInstalledApps: katalog app terpasang, di-scan ulang jika folder Applications berubah
+func GetInstalledApps() []InstalledApp {
+	dirs := appDirs()
+	sig := dirSignature(dirs)
+
+	catalogLock.Lock()
+	defer catalogLock.Unlock()
+
+	if catalog == nil || sig != catalogSig {
+		catalog = scanApps(dirs)
+		catalogSig = sig
+	}
+	return append([]InstalledApp{}, catalog...)
+}
+
+func scanApps(dirs []string) []InstalledApp {
+	apps := []InstalledApp{}
+	seen := map[string]bool{}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			if !strings.HasSuffix(e.Name(), ".app") {
+				continue
+			}
+			app, ok := readAppBundle(path)
+			if !ok || seen[app.BundleID] {
+				continue
+			}
+			seen[app.BundleID] = true
+			apps = append(apps, app)
+		}
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return strings.ToLower(apps[i].Name) < strings.ToLower(apps[j].Name)
+	})
+	return apps
+}
+
+// readAppBundle: Info.plist bisa biner, jadi dikonversi ke JSON lewat plutil
+func readAppBundle(path string) (InstalledApp, bool) {
+	out, err := exec.Command("plutil", "-convert", "json", "-o", "-", filepath.Join(path, "Contents/Info.plist")).Output()
+	if err != nil {
+		return InstalledApp{}, false
+	}
+
+	var info struct {
+		BundleID    string `json:"CFBundleIdentifier"`
+		DisplayName string `json:"CFBundleDisplayName"`
+		Version     string `json:"CFBundleShortVersionString"`
+		IconFile    string `json:"CFBundleIconFile"`
+	}
+	if json.Unmarshal(out, &info) != nil || info.BundleID == "" {
+		return InstalledApp{}, false
+	}
+
+	name := info.DisplayName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), ".app")
+	}
+
+	iconFile := info.IconFile
+	if iconFile != "" && filepath.Ext(iconFile) == "" {
+		iconFile += ".icns"
+	}
+
+	return InstalledApp{
+		Name:     name,
+		BundleID: info.BundleID,
+		Version:  info.Version,
+		Path:     path,
+		iconFile: iconFile,
+	}, true
+}
+
+// AppIcon: path PNG ikon app ukuran size x size, dibuat dari .icns lalu di-cache
+func AppIcon(bundleID string, size int) (string, error) {
+	if !bundleIDRe.MatchString(bundleID) {
+		return "", fmt.Errorf("%w: invalid bundle id: %s", ErrInvalidValue, bundleID)
+	}
+	size = max(16, min(size, 1024))
+
+	var app InstalledApp
+	found := false
+	for _, a := range GetInstalledApps() {
+		if a.BundleID == bundleID {
+			app, found = a, true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%w: app not installed: %s", ErrInvalidValue, bundleID)
+	}
+	if app.iconFile == "" {
+		// App tanpa CFBundleIconFile (mis. hanya Assets.car) belum bisa dikonversi sips
+		return "", fmt.Errorf("%w: %s has no icns icon", ErrUnsupported, app.Name)
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	cacheDir = filepath.Join(cacheDir, "Go-Agent", "icons")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+
+	// Versi masuk nama file supaya ikon baru dipakai setelah app di-update
+	out := filepath.Join(cacheDir, fmt.Sprintf("%s-%s-%d.png", bundleID, app.Version, size))
+	if _, err := os.Stat(out); err == nil {
+		return out, nil
+	}
+
+	src := filepath.Join(app.Path, "Contents/Resources", app.iconFile)
+	cmd := exec.Command("sips", "-s", "format", "png", "-Z", fmt.Sprint(size), src, "--out", out)
+	if res, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("sips error: %s", strings.TrimSpace(string(res)))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const launchTimeout = 10 * time.Second
+
+type LaunchOptions struct {
+	App         string   `json:"app"`       // Nama app, atau
+	BundleID    string   `json:"bundle_id"` // bundle ID (diutamakan)
+	Documents   []string `json:"documents"` // File/folder yang dibuka dengan app
+	URLs        []string `json:"urls"`      // URL, mis. dibuka di browser tertentu
+	Args        []string `json:"args"`      // Argumen command-line (--args)
+	NewInstance bool     `json:"new_instance"`
+	Hidden      bool     `json:"hidden"`     // Launch dalam keadaan hidden
+	Background  bool     `json:"background"` // Jangan bawa ke depan
+	Fresh       bool     `json:"fresh"`      // Jangan restore window sebelumnya
+}
+
+type LaunchResult struct {
+	BundleID string `json:"bundle_id,omitempty"`
+	PID      int    `json:"pid"` // 0 jika app tidak terdeteksi berjalan sebelum timeout
+	Running  bool   `json:"running"`
+}
+
+// LaunchApp: wrapper "open" dengan dokumen, URL, argumen dan flag launch,
+// lalu tunggu sampai proses utama app berjalan untuk mendapatkan PID-nya
+func LaunchApp(opts LaunchOptions) (LaunchResult, error) {
+	if opts.BundleID != "" && !bundleIDRe.MatchString(opts.BundleID) {
+		return LaunchResult{}, fmt.Errorf("%w: invalid bundle id: %s", ErrInvalidValue, opts.BundleID)
+	}
+	if opts.App == "" && opts.BundleID == "" && len(opts.Documents) == 0 && len(opts.URLs) == 0 {
+		return LaunchResult{}, fmt.Errorf("%w: app, bundle_id, documents or urls is required", ErrInvalidValue)
+	}
+
+	bundleID := opts.BundleID
+	if bundleID == "" && opts.App != "" {
+		bundleID = bundleIDForName(opts.App)
+	}
+
+	args := []string{}
+	switch {
+	case opts.BundleID != "":
+		args = append(args, "-b", opts.BundleID)
+	case opts.App != "":
+		args = append(args, "-a", opts.App)
+	}
+	if opts.NewInstance {
+		args = append(args, "-n")
+	}
+	if opts.Hidden {
+		args = append(args, "-j")
+	}
+	if opts.Background {
+		args = append(args, "-g")
+	}
+	if opts.Fresh {
+		args = append(args, "-F")
+	}
+
+	for _, doc := range opts.Documents {
+		if _, err := os.Stat(doc); err != nil {
+			return LaunchResult{}, fmt.Errorf("%w: document not found: %s", ErrInvalidValue, doc)
+		}
+		args = append(args, doc)
+	}
+	for _, raw := range opts.URLs {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" {
+			return LaunchResult{}, fmt.Errorf("%w: invalid url: %s", ErrInvalidValue, raw)
+		}
+		args = append(args, raw)
+	}
+	if len(opts.Args) > 0 {
+		args = append(args, "--args")
+		args = append(args, opts.Args...)
+	}
+
+	before := map[int]bool{}
+	for _, pid := range mainAppPIDs(bundleID) {
+		before[pid] = true
+	}
+
+	if out, err := exec.Command("/usr/bin/open", args...).CombinedOutput(); err != nil {
+		return LaunchResult{}, fmt.Errorf("open error: %s", strings.TrimSpace(string(out)))
+	}
+
+	res := LaunchResult{BundleID: bundleID}
+	if bundleID == "" {
+		// Dibuka dengan app default, tidak tahu proses mana yang harus ditunggu
+		return res, nil
+	}
+
+	deadline := time.Now().Add(launchTimeout)
+	for time.Now().Before(deadline) {
+		for _, pid := range mainAppPIDs(bundleID) {
+			// Instance baru harus PID baru; selain itu instance lama yang dipakai ulang juga valid
+			if !opts.NewInstance || !before[pid] {
+				res.PID = pid
+				res.Running = true
+				return res, nil
+			}
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+
+	return res, nil
+}
+
+func mainAppPIDs(bundleID string) []int {
+	if bundleID == "" {
+		return nil
+	}
+
+	var pids []int
+	for _, p := range ReadProcessTable() {
+		if p.BundleID == bundleID && isMainAppProcess(p) {
+			pids = append(pids, p.PID)
+		}
+	}
+	return pids
+}
+
+func bundleIDForName(name string) string {
+	for _, a := range GetInstalledApps() {
+		if strings.EqualFold(a.Name, name) || strings.EqualFold(strings.TrimSuffix(filepath.Base(a.Path), ".app"), name) {
+			return a.BundleID
+		}
+	}
+	return ""
+}
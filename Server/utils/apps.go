@@ -0,0 +1,113 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+type RunningApp struct {
+	Name      string `json:"name"`
+	BundleID  string `json:"bundle_id"`
+	PID       int    `json:"pid"`
+	Frontmost bool   `json:"frontmost"`
+	Hidden    bool   `json:"hidden"`
+	Windows   int    `json:"windows"`
+}
+
+type AppActionResult struct {
+	BundleID string `json:"bundle_id"`
+	Action   string `json:"action"`
+	Status   string `json:"status"` // "done", atau "pending" jika quit menunggu dialog "save changes?"
+}
+
+var bundleIDRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.\-]*$`)
+
+// ListRunningApps: aplikasi GUI (bukan background-only) yang sedang berjalan
+func ListRunningApps() ([]RunningApp, error) {
+	out, err := runJXA(`
+		const se = Application("System Events");
+		const procs = se.applicationProcesses.whose({ backgroundOnly: false })();
+		JSON.stringify(procs.map(p => ({
+			name: p.name(),
+			bundle_id: p.bundleIdentifier() || "",
+			pid: p.unixId(),
+			frontmost: p.frontmost(),
+			hidden: !p.visible(),
+			windows: p.windows.length,
+		})));
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	apps := []RunningApp{}
+	if err := json.Unmarshal([]byte(out), &apps); err != nil {
+		return nil, fmt.Errorf("invalid app list: %v", err)
+	}
+	return apps, nil
+}
+
+func findRunningApp(bundleID string) (RunningApp, bool) {
+	apps, err := ListRunningApps()
+	if err != nil {
+		return RunningApp{}, false
+	}
+	for _, a := range apps {
+		if a.BundleID == bundleID {
+			return a, true
+		}
+	}
+	return RunningApp{}, false
+}
+
+// ErrAppNotRunning: bundle ID valid tapi app tidak sedang berjalan (404)
+var ErrAppNotRunning = errors.New("app not running")
+
+// AppAction: activate, hide, unhide, quit, force-quit berdasarkan bundle ID
+func AppAction(bundleID, action string) (AppActionResult, error) {
+	if !bundleIDRe.MatchString(bundleID) {
+		return AppActionResult{}, fmt.Errorf("%w: invalid bundle id: %s", ErrInvalidValue, bundleID)
+	}
+
+	app, ok := findRunningApp(bundleID)
+	if !ok {
+		return AppActionResult{}, fmt.Errorf("%w: %s", ErrAppNotRunning, bundleID)
+	}
+
+	res := AppActionResult{BundleID: bundleID, Action: action, Status: "done"}
+	var err error
+
+	switch action {
+	case "activate":
+		err = runOsa(fmt.Sprintf(`tell application id "%s" to activate`, bundleID))
+	case "hide", "unhide":
+		err = runOsa(fmt.Sprintf(`
+			tell application "System Events"
+				set visible of (first application process whose bundle identifier is "%s") to %t
+			end tell
+		`, bundleID, action == "unhide"))
+	case "quit":
+		// Jangan tunggu balasan: app boleh menampilkan dialog "save changes?" ke user
+		err = runOsa(fmt.Sprintf(`
+			ignoring application responses
+				tell application id "%s" to quit
+			end ignoring
+		`, bundleID))
+		if err == nil && !waitExit(app.PID, 5*time.Second) {
+			res.Status = "pending"
+		}
+	case "force-quit":
+		err = CheckedSignal(app.PID, syscall.SIGKILL, false)
+	default:
+		return AppActionResult{}, fmt.Errorf("%w: unknown app action: %s", ErrInvalidValue, action)
+	}
+
+	if err != nil {
+		return AppActionResult{}, err
+	}
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strings"
+)
+
+var agentToken string
+
+// LoadAgentToken: token bersama untuk endpoint sensitif (input, clipboard, layar).
+// AGENT_TOKEN di env diutamakan; jika tidak ada, token dibaca dari file atau dibuat baru (0600).
+func LoadAgentToken(path string) (string, error) {
+	if t := os.Getenv("AGENT_TOKEN"); t != "" {
+		agentToken = t
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err == nil && strings.TrimSpace(string(data)) != "" {
+		agentToken = strings.TrimSpace(string(data))
+		return agentToken, nil
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", err
+	}
+	agentToken = token
+	return token, nil
+}
+
+// CheckToken: "Authorization: Bearer <token>", header X-Agent-Token, atau ?token= (untuk WebSocket)
+func CheckToken(r *http.Request) bool {
+	if agentToken == "" {
+		return false
+	}
+
+	got := r.Header.Get("X-Agent-Token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		got = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if got == "" {
+		got = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(agentToken)) == 1
+}
@@ -0,0 +1,208 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported: fitur tidak tersedia di Mac/display ini
+var ErrUnsupported = errors.New("unsupported")
+
+type Display struct {
+	ID         string `json:"id"` // "builtin:0", "ddc:1" atau "display:<nama>" jika tidak didukung
+	Name       string `json:"name"`
+	BuiltIn    bool   `json:"builtin"`
+	Brightness int    `json:"brightness"`       // 0-100, -1 jika tidak bisa dibaca
+	Method     string `json:"method,omitempty"` // "brightness" (panel internal) atau "ddc"
+	Status     string `json:"status"`           // "ok" atau "unsupported"
+	Reason     string `json:"reason,omitempty"`
+}
+
+var (
+	brightnessLineRe = regexp.MustCompile(`^display (\d+): brightness ([0-9.]+)`)
+	displayLineRe    = regexp.MustCompile(`^display (\d+): (.*)$`)
+	ddcListRe        = regexp.MustCompile(`^\[(\d+)\]\s+(.+?)(\s+\([0-9A-Fa-f-]+\))?$`)
+)
+
+// GetDisplays: semua display dengan brightness saat ini.
+// Panel internal lewat CLI "brightness", monitor eksternal lewat DDC/CI ("m1ddc").
+// Keduanya tool pihak ketiga: brew install brightness m1ddc
+func GetDisplays() []Display {
+	displays := append(builtinDisplays(), ddcDisplays()...)
+
+	// Display lain yang terdeteksi sistem tapi tidak bisa dikontrol
+	for _, sd := range systemDisplays() {
+		covered := false
+		for _, d := range displays {
+			if d.Name == sd.Name || (d.BuiltIn && sd.Internal) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			displays = append(displays, Display{
+				ID:         "display:" + sd.Name,
+				Name:       sd.Name,
+				BuiltIn:    sd.Internal,
+				Brightness: -1,
+				Status:     "unsupported",
+				Reason:     unsupportedReason(sd.Internal),
+			})
+		}
+	}
+
+	return displays
+}
+
+func unsupportedReason(internal bool) string {
+	tool := "m1ddc"
+	if internal {
+		tool = "brightness"
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return tool + " CLI not installed"
+	}
+	if internal {
+		return "brightness CLI cannot control this display"
+	}
+	return "no DDC/CI control available for this display"
+}
+
+func builtinDisplays() []Display {
+	out, err := exec.Command("brightness", "-l").Output()
+	if err != nil {
+		return nil
+	}
+
+	builtin := map[string]bool{}
+	levels := map[string]float64{}
+	var order []string
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if m := brightnessLineRe.FindStringSubmatch(line); m != nil {
+			levels[m[1]], _ = strconv.ParseFloat(m[2], 64)
+			continue
+		}
+		if m := displayLineRe.FindStringSubmatch(line); m != nil && strings.Contains(m[2], "built-in") {
+			builtin[m[1]] = true
+			order = append(order, m[1])
+		}
+	}
+
+	displays := []Display{}
+	for _, idx := range order {
+		level, ok := levels[idx]
+		if !builtin[idx] || !ok {
+			continue
+		}
+		displays = append(displays, Display{
+			ID:         "builtin:" + idx,
+			Name:       "Built-in Display",
+			BuiltIn:    true,
+			Brightness: int(math.Round(level * 100)),
+			Method:     "brightness",
+			Status:     "ok",
+		})
+	}
+	return displays
+}
+
+func ddcDisplays() []Display {
+	out, err := exec.Command("m1ddc", "display", "list").Output()
+	if err != nil {
+		return nil
+	}
+
+	displays := []Display{}
+	for _, line := range strings.Split(string(out), "\n") {
+		m := ddcListRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		d := Display{ID: "ddc:" + m[1], Name: m[2], Brightness: -1, Method: "ddc", Status: "ok"}
+		val, err := exec.Command("m1ddc", "display", m[1], "get", "luminance").Output()
+		if v, convErr := strconv.Atoi(strings.TrimSpace(string(val))); err == nil && convErr == nil {
+			d.Brightness = v
+		} else {
+			// Monitor ada tapi tidak menjawab DDC/CI (mis. lewat dock/adapter tertentu)
+			d.Status = "unsupported"
+			d.Reason = "display did not respond to DDC/CI"
+		}
+		displays = append(displays, d)
+	}
+	return displays
+}
+
+type systemDisplay struct {
+	Name     string
+	Internal bool
+}
+
+func systemDisplays() []systemDisplay {
+	out, err := exec.Command("system_profiler", "SPDisplaysDataType", "-json").Output()
+	if err != nil {
+		return nil
+	}
+
+	var data struct {
+		SPDisplaysDataType []struct {
+			Displays []struct {
+				Name       string `json:"_name"`
+				Connection string `json:"spdisplays_connection_type"`
+			} `json:"spdisplays_ndrvs"`
+		} `json:"SPDisplaysDataType"`
+	}
+	if json.Unmarshal(out, &data) != nil {
+		return nil
+	}
+
+	var displays []systemDisplay
+	for _, gpu := range data.SPDisplaysDataType {
+		for _, d := range gpu.Displays {
+			displays = append(displays, systemDisplay{
+				Name:     d.Name,
+				Internal: d.Connection == "spdisplays_internal",
+			})
+		}
+	}
+	return displays
+}
+
+// SetBrightness: nilai absolut 0-100 untuk satu display
+func SetBrightness(id string, value int) (Display, error) {
+	if value < 0 || value > 100 {
+		return Display{}, fmt.Errorf("%w: brightness must be between 0 and 100, got %d", ErrInvalidValue, value)
+	}
+
+	kind, idx, _ := strings.Cut(id, ":")
+	var cmd *exec.Cmd
+	switch kind {
+	case "builtin":
+		cmd = exec.Command("brightness", "-d", idx, strconv.FormatFloat(float64(value)/100, 'f', 2, 64))
+	case "ddc":
+		cmd = exec.Command("m1ddc", "display", idx, "set", "luminance", strconv.Itoa(value))
+	default:
+		return Display{}, fmt.Errorf("%w: display %s has no brightness control", ErrUnsupported, id)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return Display{}, fmt.Errorf("set brightness failed: %s", strings.TrimSpace(string(out)))
+	}
+
+	for _, d := range GetDisplays() {
+		if d.ID == id {
+			if d.Status != "ok" {
+				return d, fmt.Errorf("%w: %s", ErrUnsupported, d.Reason)
+			}
+			return d, nil
+		}
+	}
+	return Display{}, fmt.Errorf("%w: display not found: %s", ErrInvalidValue, id)
+}
@@ -0,0 +1,295 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Kategori proses
+const (
+	CategorySystem     = "System"
+	CategoryUser       = "User"
+	CategoryDeveloper  = "Developer"
+	CategoryBrowser    = "Browser"
+	CategoryBackground = "Background"
+)
+
+// ClassRule: semua kondisi yang diisi harus cocok. Rule pertama yang cocok menang.
+type ClassRule struct {
+	Name       string   `json:"name"`
+	Category   string   `json:"category"`
+	Names      []string `json:"names,omitempty"`       // Nama executable persis
+	PathPrefix []string `json:"path_prefix,omitempty"` // "~/" diganti home directory
+	BundleID   []string `json:"bundle_id,omitempty"`   // Boleh diakhiri "*" untuk prefix
+	User       string   `json:"user,omitempty"`        // "$user" = user yang login
+	Regex      string   `json:"regex,omitempty"`       // Dicocokkan ke nama executable
+	Self       bool     `json:"self,omitempty"`        // Hanya proses agent ini sendiri
+
+	re *regexp.Regexp
+}
+
+type ClassifierConfig struct {
+	Rules           []ClassRule `json:"rules"`
+	ReplaceDefaults bool        `json:"replace_defaults"` // false = rules ditambahkan di depan rule bawaan
+}
+
+var defaultClassRules = []ClassRule{
+	{
+		// Agent sendiri (mis. "Go-Agent") jangan sampai ikut root-daemons/background-helpers
+		Name:     "agent",
+		Category: CategoryUser,
+		Self:     true,
+	},
+	{
+		Name:     "browsers",
+		Category: CategoryBrowser,
+		BundleID: []string{
+			"com.google.Chrome*", "com.apple.Safari*", "org.mozilla.firefox*",
+			"com.brave.Browser*", "com.microsoft.edgemac*", "company.thebrowser.Browser*",
+			"com.operasoftware.Opera*", "com.vivaldi.Vivaldi*",
+		},
+	},
+	{
+		Name:     "developer-apps",
+		Category: CategoryDeveloper,
+		BundleID: []string{
+			"com.microsoft.VSCode*", "com.apple.dt.*", "com.docker.docker*",
+			"com.jetbrains.*", "com.googlecode.iterm2*", "com.apple.Terminal*",
+			"dev.warp.*", "com.sublimetext.*", "dev.zed.*",
+		},
+	},
+	{
+		Name:     "developer-tools",
+		Category: CategoryDeveloper,
+		Regex:    `^(go|gopls|node|npm|deno|bun|python[0-9.]*|ruby|java|cargo|rustc|rust-analyzer|clang|swift|make|git|docker|com\.docker\..*|qemu-system-.*)$`,
+	},
+	{
+		Name:     "system-processes",
+		Category: CategorySystem,
+		Names:    systemProcessNames(),
+	},
+	{
+		Name:       "system-paths",
+		Category:   CategorySystem,
+		PathPrefix: []string{"/System/", "/usr/libexec/", "/usr/sbin/", "/sbin/", "/Library/Apple/"},
+	},
+	{
+		Name:     "root-daemons",
+		Category: CategoryBackground,
+		User:     "root",
+	},
+	{
+		Name:       "applications",
+		Category:   CategoryUser,
+		PathPrefix: []string{"/Applications/", "~/Applications/"},
+	},
+	{
+		Name:     "background-helpers",
+		Category: CategoryBackground,
+		// Kata terpisah spasi/titik atau CamelCase ("UserEventAgent"), bukan nama produk ber-tanda hubung
+		Regex: `(^|[a-z. ])(Helper|Service|Daemon|Agent)( \(.*\))?$`,
+	},
+}
+
+var (
+	classRules = mustCompileRules(defaultClassRules)
+	classLock  sync.RWMutex
+
+	bundleIDs     = map[string]string{}
+	bundleIDsLock sync.Mutex
+)
+
+func systemProcessNames() []string {
+	names := make([]string, 0, len(systemProcesses))
+	for name := range systemProcesses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mustCompileRules(rules []ClassRule) []ClassRule {
+	compiled, err := compileRules(rules)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
+
+func compileRules(rules []ClassRule) ([]ClassRule, error) {
+	home, _ := os.UserHomeDir()
+	compiled := make([]ClassRule, len(rules))
+
+	for i, r := range rules {
+		if r.Name == "" || r.Category == "" {
+			return nil, fmt.Errorf("rule #%d needs a name and a category", i+1)
+		}
+		// Rule tanpa kondisi cocok dengan semua proses dan menutupi semua rule sesudahnya
+		if len(r.Names) == 0 && len(r.PathPrefix) == 0 && len(r.BundleID) == 0 && r.User == "" && r.Regex == "" && !r.Self {
+			return nil, fmt.Errorf("rule %s needs at least one of names, path_prefix, bundle_id, user, regex or self", r.Name)
+		}
+		if r.Regex != "" {
+			re, err := regexp.Compile(r.Regex)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: invalid regex: %v", r.Name, err)
+			}
+			r.re = re
+		}
+
+		prefixes := make([]string, len(r.PathPrefix))
+		for j, p := range r.PathPrefix {
+			if strings.HasPrefix(p, "~/") && home != "" {
+				p = home + p[1:]
+			}
+			prefixes[j] = p
+		}
+		r.PathPrefix = prefixes
+
+		compiled[i] = r
+	}
+
+	return compiled, nil
+}
+
+// LoadClassifierRules: baca rule tambahan dari file JSON. File tidak ada = rule bawaan
+func LoadClassifierRules(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var cfg ClassifierConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Errorf("invalid classifier config %s: %v", path, err)
+	}
+
+	rules := cfg.Rules
+	if !cfg.ReplaceDefaults {
+		rules = append(rules, defaultClassRules...)
+	}
+
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return fmt.Errorf("invalid classifier config %s: %v", path, err)
+	}
+
+	classLock.Lock()
+	classRules = compiled
+	classLock.Unlock()
+	return nil
+}
+
+// GetClassifierRules: rule aktif sesuai urutan evaluasi
+func GetClassifierRules() []ClassRule {
+	classLock.RLock()
+	defer classLock.RUnlock()
+	return append([]ClassRule{}, classRules...)
+}
+
+// classifyProcess: kategori dan nama rule yang cocok
+func classifyProcess(p Process) (string, string) {
+	classLock.RLock()
+	rules := classRules
+	classLock.RUnlock()
+
+	for _, r := range rules {
+		if r.matches(p) {
+			return r.Category, r.Name
+		}
+	}
+	return CategoryUser, "default"
+}
+
+func (r ClassRule) matches(p Process) bool {
+	if r.Self && !isSelf(p) {
+		return false
+	}
+	if len(r.Names) > 0 && !containsName(r.Names, p.Name) {
+		return false
+	}
+	if len(r.PathPrefix) > 0 && !hasAnyPrefix(p.Path, r.PathPrefix) {
+		return false
+	}
+	if len(r.BundleID) > 0 && !matchBundleID(p.BundleID, r.BundleID) {
+		return false
+	}
+	if r.User != "" {
+		want := r.User
+		if want == "$user" {
+			want = CurrentUser()
+		}
+		if p.User != want {
+			return false
+		}
+	}
+	if r.re != nil && !r.re.MatchString(p.Name) {
+		return false
+	}
+	return true
+}
+
+func isSelf(p Process) bool {
+	if p.PID == os.Getpid() {
+		return true
+	}
+	exe, err := os.Executable()
+	return err == nil && p.Path == exe
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchBundleID(id string, patterns []string) bool {
+	if id == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(id, prefix) {
+				return true
+			}
+		} else if id == p {
+			return true
+		}
+	}
+	return false
+}
+
+// BundleIDOf: CFBundleIdentifier dari Info.plist bundle, di-cache per path
+func BundleIDOf(bundle string) string {
+	if bundle == "" {
+		return ""
+	}
+
+	bundleIDsLock.Lock()
+	id, ok := bundleIDs[bundle]
+	bundleIDsLock.Unlock()
+	if ok {
+		return id
+	}
+
+	out, err := exec.Command("defaults", "read", bundle+"/Contents/Info", "CFBundleIdentifier").Output()
+	if err == nil {
+		id = strings.TrimSpace(string(out))
+	}
+
+	bundleIDsLock.Lock()
+	bundleIDs[bundle] = id
+	bundleIDsLock.Unlock()
+	return id
+}
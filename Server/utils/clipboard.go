@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TopicClipboard = "clipboard"
+
+	clipboardPoll = 1500 * time.Millisecond
+	maxHistory    = 100
+	previewLength = 500
+)
+
+// Tipe yang dipakai password manager untuk menandai isi rahasia (nspasteboard.org)
+var concealedTypes = []string{"org.nspasteboard.ConcealedType", "org.nspasteboard.TransientType"}
+
+type Clipboard struct {
+	ChangeCount int      `json:"change_count"`
+	Types       []string `json:"types"`
+	Text        string   `json:"text"`
+	HasRTF      bool     `json:"has_rtf"`
+	HasImage    bool     `json:"has_image"`
+	Concealed   bool     `json:"concealed"`
+}
+
+type ClipboardEntry struct {
+	TS       int64  `json:"ts"` // Unix milidetik
+	Text     string `json:"text,omitempty"`
+	HasRTF   bool   `json:"has_rtf"`
+	HasImage bool   `json:"has_image"`
+}
+
+type ClipboardSettings struct {
+	History          bool `json:"history"`      // Simpan riwayat di memori, default mati
+	HistorySize      int  `json:"history_size"` // Maksimal 100
+	ExcludeConcealed bool `json:"exclude_concealed"`
+}
+
+var (
+	clipSettings = ClipboardSettings{History: false, HistorySize: 20, ExcludeConcealed: true}
+	clipHistory  []ClipboardEntry
+	clipLast     int
+	clipLock     sync.Mutex
+)
+
+func readPasteboard() (Clipboard, error) {
+	out, err := runJXA(`
+		ObjC.import("AppKit");
+		const pb = $.NSPasteboard.generalPasteboard;
+		const types = ObjC.deepUnwrap(pb.types) || [];
+		const text = pb.stringForType($.NSPasteboardTypeString);
+		JSON.stringify({
+			change_count: pb.changeCount,
+			types: types,
+			text: text.isNil() ? "" : text.js,
+		});
+	`)
+	if err != nil {
+		return Clipboard{}, err
+	}
+
+	var c Clipboard
+	if err := json.Unmarshal([]byte(out), &c); err != nil {
+		return Clipboard{}, fmt.Errorf("invalid pasteboard output: %v", err)
+	}
+	for _, t := range c.Types {
+		switch {
+		case t == "public.rtf":
+			c.HasRTF = true
+		case t == "public.png" || t == "public.tiff":
+			c.HasImage = true
+		case containsName(concealedTypes, t):
+			c.Concealed = true
+		}
+	}
+	return c, nil
+}
+
+// GetClipboard: isi clipboard saat ini. Isi rahasia disembunyikan jika ExcludeConcealed aktif
+func GetClipboard() (Clipboard, error) {
+	c, err := readPasteboard()
+	if err != nil {
+		return Clipboard{}, err
+	}
+
+	clipLock.Lock()
+	exclude := clipSettings.ExcludeConcealed
+	clipLock.Unlock()
+
+	if c.Concealed && exclude {
+		c.Text = ""
+		c.HasRTF = false
+		c.HasImage = false
+	}
+	return c, nil
+}
+
+// GetClipboardRTF: "pbpaste -Prefer rtf" mengembalikan teks biasa jika tidak ada RTF
+func GetClipboardRTF() ([]byte, error) {
+	c, err := GetClipboard()
+	if err != nil {
+		return nil, err
+	}
+	if !c.HasRTF {
+		return nil, fmt.Errorf("%w: clipboard has no RTF content", ErrInvalidValue)
+	}
+	return exec.Command("pbpaste", "-Prefer", "rtf").Output()
+}
+
+// GetClipboardPNG: gambar di clipboard sebagai PNG (TIFF, mis. dari screenshot, dikonversi)
+func GetClipboardPNG() ([]byte, error) {
+	c, err := GetClipboard()
+	if err != nil {
+		return nil, err
+	}
+	if !c.HasImage {
+		return nil, fmt.Errorf("%w: clipboard has no image", ErrInvalidValue)
+	}
+
+	tmp := filepath.Join(os.TempDir(), fmt.Sprintf("agent-clip-%d.png", time.Now().UnixNano()))
+	defer os.Remove(tmp)
+
+	_, err = runJXA(fmt.Sprintf(`
+		ObjC.import("AppKit");
+		const pb = $.NSPasteboard.generalPasteboard;
+		let data = pb.dataForType($.NSPasteboardTypePNG);
+		if (data.isNil()) {
+			const rep = $.NSBitmapImageRep.imageRepWithData(pb.dataForType($.NSPasteboardTypeTIFF));
+			data = rep.representationUsingTypeProperties($.NSBitmapImageFileTypePNG, $());
+		}
+		data.writeToFileAtomically(%s, true);
+	`, jsString(tmp)))
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(tmp)
+}
+
+// SetClipboard: kind = text, rtf atau png
+func SetClipboard(kind string, data []byte) error {
+	switch kind {
+	case "text", "rtf":
+		// pbcopy otomatis menyimpan sebagai RTF jika data diawali header "{\rtf"
+		if kind == "rtf" && !strings.HasPrefix(string(data), `{\rtf`) {
+			return fmt.Errorf("%w: data is not RTF", ErrInvalidValue)
+		}
+		cmd := exec.Command("pbcopy")
+		// Tanpa locale UTF-8 (mis. dari launchd) pbcopy merusak teks non-ASCII
+		cmd.Env = append(os.Environ(), "LANG=en_US.UTF-8")
+		cmd.Stdin = strings.NewReader(string(data))
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("pbcopy error: %s", strings.TrimSpace(string(out)))
+		}
+		return nil
+
+	case "png":
+		if !strings.HasPrefix(string(data), "\x89PNG") {
+			return fmt.Errorf("%w: data is not a PNG image", ErrInvalidValue)
+		}
+		tmp := filepath.Join(os.TempDir(), fmt.Sprintf("agent-clip-%d.png", time.Now().UnixNano()))
+		if err := os.WriteFile(tmp, data, 0600); err != nil {
+			return err
+		}
+		defer os.Remove(tmp)
+
+		_, err := runJXA(fmt.Sprintf(`
+			ObjC.import("AppKit");
+			const pb = $.NSPasteboard.generalPasteboard;
+			pb.clearContents;
+			pb.setDataForType($.NSData.dataWithContentsOfFile(%s), $.NSPasteboardTypePNG);
+		`, jsString(tmp)))
+		return err
+
+	default:
+		return fmt.Errorf("%w: unsupported clipboard type: %s", ErrInvalidValue, kind)
+	}
+}
+
+func GetClipboardHistory() []ClipboardEntry {
+	clipLock.Lock()
+	defer clipLock.Unlock()
+	return append([]ClipboardEntry{}, clipHistory...)
+}
+
+func GetClipboardSettings() ClipboardSettings {
+	clipLock.Lock()
+	defer clipLock.Unlock()
+	return clipSettings
+}
+
+func SetClipboardSettings(s ClipboardSettings) (ClipboardSettings, error) {
+	if s.HistorySize < 0 || s.HistorySize > maxHistory {
+		return ClipboardSettings{}, fmt.Errorf("%w: history_size must be between 0 and %d", ErrInvalidValue, maxHistory)
+	}
+
+	clipLock.Lock()
+	defer clipLock.Unlock()
+
+	clipSettings = s
+	if !s.History {
+		clipHistory = nil
+	} else if len(clipHistory) > s.HistorySize {
+		clipHistory = clipHistory[:s.HistorySize]
+	}
+	return clipSettings, nil
+}
+
+// StartClipboardWatcher: cek changeCount berkala, hanya jika riwayat aktif atau ada subscriber
+func StartClipboardWatcher() {
+	go func() {
+		for {
+			time.Sleep(clipboardPoll)
+
+			settings := GetClipboardSettings()
+			if !settings.History && !HasSubscribers(TopicClipboard) {
+				continue
+			}
+			checkClipboard(settings)
+		}
+	}()
+}
+
+func checkClipboard(settings ClipboardSettings) {
+	c, err := readPasteboard()
+	if err != nil {
+		return
+	}
+
+	clipLock.Lock()
+	changed := clipLast != 0 && c.ChangeCount != clipLast
+	clipLast = c.ChangeCount
+	clipLock.Unlock()
+
+	if !changed || (c.Concealed && settings.ExcludeConcealed) {
+		return
+	}
+
+	text := c.Text
+	if len([]rune(text)) > previewLength {
+		text = string([]rune(text)[:previewLength])
+	}
+	entry := ClipboardEntry{TS: time.Now().UnixMilli(), Text: text, HasRTF: c.HasRTF, HasImage: c.HasImage}
+
+	if settings.History && settings.HistorySize > 0 {
+		clipLock.Lock()
+		clipHistory = append([]ClipboardEntry{entry}, clipHistory...)
+		if len(clipHistory) > settings.HistorySize {
+			clipHistory = clipHistory[:settings.HistorySize]
+		}
+		clipLock.Unlock()
+	}
+
+	PublishEvent(TopicClipboard, "change", entry)
+}
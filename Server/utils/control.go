@@ -1,19 +1,42 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // Helper: jalankan osascript dengan full path
 func runOsa(script string) error {
+	_, err := runOsaOutput(script)
+	return err
+}
+
+// Helper: sama seperti runOsa tapi mengembalikan output script
+func runOsaOutput(script string) (string, error) {
 	cmd := exec.Command("/usr/bin/osascript", "-e", script)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		fmt.Printf("❌ GAGAL: %s\nOutput: %s\n", script, string(output))
-		return fmt.Errorf("osascript error: %s", string(output))
+		return "", fmt.Errorf("osascript error: %s", string(output))
 	}
-	return nil
+	return strings.TrimSpace(string(output)), nil
+}
+
+// Helper: jalankan JavaScript for Automation, dipakai jika hasil perlu JSON.stringify
+func runJXA(script string) (string, error) {
+	cmd := exec.Command("/usr/bin/osascript", "-l", "JavaScript", "-e", script)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		fmt.Printf("❌ GAGAL (JXA): %s\nOutput: %s\n", script, string(output))
+		return "", fmt.Errorf("osascript error: %s", string(output))
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 /* =====================
@@ -31,12 +54,281 @@ func ControlVolume(action string, value int) error {
 	case "mute":
 		script = "set volume output muted not (output muted of (get volume settings))"
 	case "set":
+		if err := checkVolume(value); err != nil {
+			return err
+		}
 		script = fmt.Sprintf("set volume output volume %d", value)
+	default:
+		return fmt.Errorf("unknown volume action: %s", action)
 	}
 
 	return runOsa(script)
 }
 
+type AudioState struct {
+	OutputVolume int  `json:"output_volume"`
+	InputVolume  int  `json:"input_volume"`
+	AlertVolume  int  `json:"alert_volume"`
+	Muted        bool `json:"muted"`
+}
+
+// AudioUpdate: field nil = tidak diubah
+type AudioUpdate struct {
+	OutputVolume *int  `json:"output_volume"`
+	InputVolume  *int  `json:"input_volume"`
+	AlertVolume  *int  `json:"alert_volume"`
+	Muted        *bool `json:"muted"`
+}
+
+// ErrInvalidValue: nilai di luar range, handler mengirim 400 untuk error ini
+var ErrInvalidValue = errors.New("invalid value")
+
+func checkVolume(v int) error {
+	if v < 0 || v > 100 {
+		return fmt.Errorf("%w: volume must be between 0 and 100, got %d", ErrInvalidValue, v)
+	}
+	return nil
+}
+
+// GetAudioState: baca "output volume:50, input volume:75, alert volume:100, output muted:false"
+func GetAudioState() (AudioState, error) {
+	out, err := runOsaOutput("get volume settings")
+	if err != nil {
+		return AudioState{}, err
+	}
+
+	var state AudioState
+	for _, part := range strings.Split(out, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			continue
+		}
+		n, _ := strconv.Atoi(val) // "missing value" jika tidak ada device input -> 0
+
+		switch key {
+		case "output volume":
+			state.OutputVolume = n
+		case "input volume":
+			state.InputVolume = n
+		case "alert volume":
+			state.AlertVolume = n
+		case "output muted":
+			state.Muted = val == "true"
+		}
+	}
+
+	return state, nil
+}
+
+// SetAudio: validasi semua nilai dulu, lalu terapkan dalam satu script
+func SetAudio(u AudioUpdate) (AudioState, error) {
+	var cmds []string
+
+	for _, v := range []struct {
+		value *int
+		param string
+	}{
+		{u.OutputVolume, "output volume"},
+		{u.InputVolume, "input volume"},
+		{u.AlertVolume, "alert volume"},
+	} {
+		if v.value == nil {
+			continue
+		}
+		if err := checkVolume(*v.value); err != nil {
+			return AudioState{}, fmt.Errorf("%s: %w", v.param, err)
+		}
+		cmds = append(cmds, fmt.Sprintf("set volume %s %d", v.param, *v.value))
+	}
+	if u.Muted != nil {
+		cmds = append(cmds, fmt.Sprintf("set volume output muted %t", *u.Muted))
+	}
+
+	if len(cmds) > 0 {
+		if err := runOsa(strings.Join(cmds, "\n")); err != nil {
+			return AudioState{}, err
+		}
+	}
+
+	return GetAudioState()
+}
+
+/* =====================
+   🎧 AUDIO DEVICES
+===================== */
+
+const TopicAudio = "audio"
+
+type AudioDevice struct {
+	Name          string  `json:"name"`
+	UID           string  `json:"uid,omitempty"` // Butuh SwitchAudioSource
+	Manufacturer  string  `json:"manufacturer,omitempty"`
+	Transport     string  `json:"transport"` // builtin, usb, bluetooth, airplay, virtual, ...
+	SampleRate    float64 `json:"sample_rate"`
+	Input         bool    `json:"input"`
+	Output        bool    `json:"output"`
+	DefaultInput  bool    `json:"default_input"`
+	DefaultOutput bool    `json:"default_output"`
+	DefaultSystem bool    `json:"default_system"` // Device untuk alert/system sound
+}
+
+// Format output "system_profiler SPAudioDataType -json"
+type spAudioDevice struct {
+	Name          string  `json:"_name"`
+	Manufacturer  string  `json:"coreaudio_device_manufacturer"`
+	Transport     string  `json:"coreaudio_device_transport"`
+	SampleRate    float64 `json:"coreaudio_device_srate"`
+	InputCh       int     `json:"coreaudio_device_input"`
+	OutputCh      int     `json:"coreaudio_device_output"`
+	DefaultInput  string  `json:"coreaudio_default_audio_input_device"`
+	DefaultOutput string  `json:"coreaudio_default_audio_output_device"`
+	DefaultSystem string  `json:"coreaudio_default_audio_system_device"`
+}
+
+// switchAudioSource: https://github.com/deweller/switchaudio-osx (brew install switchaudio-osx)
+func switchAudioSource(args ...string) (string, error) {
+	path, err := exec.LookPath("SwitchAudioSource")
+	if err != nil {
+		return "", fmt.Errorf("SwitchAudioSource not installed (brew install switchaudio-osx)")
+	}
+	out, err := exec.Command(path, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("SwitchAudioSource error: %s", strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+func ListAudioDevices() ([]AudioDevice, error) {
+	out, err := exec.Command("system_profiler", "SPAudioDataType", "-json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("system_profiler error: %v", err)
+	}
+
+	var data struct {
+		SPAudioDataType []struct {
+			Items []spAudioDevice `json:"_items"`
+		} `json:"SPAudioDataType"`
+	}
+	if err := json.Unmarshal(out, &data); err != nil {
+		return nil, fmt.Errorf("invalid system_profiler output: %v", err)
+	}
+
+	uids := audioDeviceUIDs()
+	devices := []AudioDevice{}
+	for _, group := range data.SPAudioDataType {
+		for _, d := range group.Items {
+			devices = append(devices, AudioDevice{
+				Name:          d.Name,
+				UID:           uids[d.Name],
+				Manufacturer:  d.Manufacturer,
+				Transport:     strings.TrimPrefix(d.Transport, "coreaudio_device_type_"),
+				SampleRate:    d.SampleRate,
+				Input:         d.InputCh > 0,
+				Output:        d.OutputCh > 0,
+				DefaultInput:  d.DefaultInput == "spaudio_yes",
+				DefaultOutput: d.DefaultOutput == "spaudio_yes",
+				DefaultSystem: d.DefaultSystem == "spaudio_yes",
+			})
+		}
+	}
+
+	return devices, nil
+}
+
+// audioDeviceUIDs: nama -> UID, kosong jika SwitchAudioSource tidak terpasang
+func audioDeviceUIDs() map[string]string {
+	uids := map[string]string{}
+
+	out, err := switchAudioSource("-a", "-f", "json")
+	if err != nil {
+		return uids
+	}
+
+	// Satu objek JSON per baris: {"name": "...", "type": "output", "id": "91", "uid": "..."}
+	for _, line := range strings.Split(out, "\n") {
+		var d struct {
+			Name string `json:"name"`
+			UID  string `json:"uid"`
+		}
+		if json.Unmarshal([]byte(line), &d) == nil && d.Name != "" {
+			uids[d.Name] = d.UID
+		}
+	}
+	return uids
+}
+
+// SetDefaultAudioDevice: kind = output, input atau system; device = nama atau UID
+func SetDefaultAudioDevice(kind, device string) error {
+	switch kind {
+	case "output", "input", "system":
+	default:
+		return fmt.Errorf("%w: type must be output, input or system", ErrInvalidValue)
+	}
+	if device == "" {
+		return fmt.Errorf("%w: device is required", ErrInvalidValue)
+	}
+
+	devices, err := ListAudioDevices()
+	if err != nil {
+		return err
+	}
+	for _, d := range devices {
+		if d.Name != device && (d.UID == "" || d.UID != device) {
+			continue
+		}
+		if (kind == "input" && !d.Input) || (kind != "input" && !d.Output) {
+			return fmt.Errorf("%w: %s has no %s channels", ErrInvalidValue, d.Name, kind)
+		}
+		if _, err := switchAudioSource("-t", kind, "-s", d.Name); err != nil {
+			return err
+		}
+		checkAudioDevices()
+		return nil
+	}
+
+	return fmt.Errorf("%w: audio device not found: %s", ErrInvalidValue, device)
+}
+
+var (
+	lastAudioDevices string
+	audioDevicesLock sync.Mutex
+)
+
+// StartAudioDeviceWatcher: publish event saat device dicolok/dicabut atau default berubah
+func StartAudioDeviceWatcher() {
+	go func() {
+		for {
+			time.Sleep(5 * time.Second)
+
+			// system_profiler berat, hanya dijalankan selama ada client yang mendengarkan
+			if !HasSubscribers(TopicAudio) {
+				audioDevicesLock.Lock()
+				lastAudioDevices = ""
+				audioDevicesLock.Unlock()
+				continue
+			}
+			checkAudioDevices()
+		}
+	}()
+}
+
+func checkAudioDevices() {
+	devices, err := ListAudioDevices()
+	if err != nil {
+		return
+	}
+	sig, _ := json.Marshal(devices)
+
+	audioDevicesLock.Lock()
+	changed := lastAudioDevices != "" && lastAudioDevices != string(sig)
+	lastAudioDevices = string(sig)
+	audioDevicesLock.Unlock()
+
+	if changed {
+		PublishEvent(TopicAudio, "devices_changed", devices)
+	}
+}
+
 /* =====================
    ☀️ BRIGHTNESS CONTROL
 ===================== */
@@ -67,32 +359,34 @@ func OpenApp(appName string) error {
 	return cmd.Run()
 }
 
+// OpenBundle: sama seperti OpenApp tapi berdasarkan bundle ID (com.obsproject.obs-studio)
+func OpenBundle(bundleID string) error {
+	cmd := exec.Command("/usr/bin/open", "-b", bundleID)
+	return cmd.Run()
+}
+
 /* =====================
-   🎵 MEDIA KEY CONTROL (FIXED)
+   🎵 MEDIA KEY CONTROL
 ===================== */
 
+// SendMediaKey: playpause/next/prev ke player yang sedang aktif.
+// Dulu memakai "key code" yang sebenarnya tombol F, sekarang langsung ke player lewat SendMediaCommand.
 func SendMediaKey(key string) error {
-	var keyCode int
-
 	switch key {
-	case "playpause":
-		keyCode = 20
-	case "next":
-		keyCode = 19
-	case "prev":
-		keyCode = 18
+	case "playpause", "next", "prev":
 	default:
 		return nil
 	}
 
-	// NSEvent media key (INI YANG BENAR)
-	script := fmt.Sprintf(`
-		tell application "System Events"
-			key down (key code %d)
-			delay 0.05
-			key up (key code %d)
-		end tell
-	`, keyCode, keyCode)
+	_, err := SendMediaCommand(MediaCommand{Action: key})
+	if !errors.Is(err, exec.ErrNotFound) {
+		return err
+	}
 
-	return runOsa(script)
+	// media-control belum terinstall: jika tidak ada yang diputar pakai Music (sama seperti library),
+	// player lain tidak bisa dikontrol tanpa media-control
+	if GetCachedMediaInfo().BundleID != "" {
+		return fmt.Errorf("%w: media-control not installed (https://github.com/ungive/media-control)", ErrUnsupported)
+	}
+	return scriptedMediaCommand("Music", MediaCommand{Action: key})
 }
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// Event: satu kejadian dari subsistem agent (process, media, audio, dst)
+type Event struct {
+	Topic string `json:"topic"`
+	Type  string `json:"type"`
+	TS    int64  `json:"ts"` // Unix milidetik
+	Data  any    `json:"data"`
+}
+
+type subscriber struct {
+	ch     chan Event
+	topics map[string]bool // Kosong = semua topic
+}
+
+var (
+	subscribers = map[*subscriber]bool{}
+	subLock     sync.Mutex
+)
+
+// Topic berisi data pribadi: hanya dikirim ke subscriber yang memintanya secara eksplisit
+var privateTopics = map[string]bool{TopicClipboard: true}
+
+// IsPrivateTopic: topic yang butuh token agent untuk di-subscribe
+func IsPrivateTopic(topic string) bool {
+	return privateTopics[topic]
+}
+
+func (s *subscriber) wants(topic string) bool {
+	if len(s.topics) == 0 {
+		return !privateTopics[topic]
+	}
+	return s.topics[topic]
+}
+
+// PublishEvent: kirim ke semua subscriber. Subscriber yang lambat dilewati, bukan ditunggu
+func PublishEvent(topic, typ string, data any) {
+	ev := Event{Topic: topic, Type: typ, TS: time.Now().UnixMilli(), Data: data}
+
+	subLock.Lock()
+	defer subLock.Unlock()
+
+	for s := range subscribers {
+		if !s.wants(topic) {
+			continue
+		}
+		select {
+		case s.ch <- ev:
+		default:
+		}
+	}
+}
+
+// SubscribeEvents: channel event untuk topic tertentu. Panggil fungsi cancel saat selesai
+func SubscribeEvents(topics ...string) (<-chan Event, func()) {
+	s := &subscriber{ch: make(chan Event, 64), topics: map[string]bool{}}
+	for _, t := range topics {
+		if t != "" {
+			s.topics[t] = true
+		}
+	}
+
+	subLock.Lock()
+	subscribers[s] = true
+	subLock.Unlock()
+
+	cancel := func() {
+		subLock.Lock()
+		defer subLock.Unlock()
+		if subscribers[s] {
+			delete(subscribers, s)
+			close(s.ch)
+		}
+	}
+	return s.ch, cancel
+}
+
+// HasSubscribers: ada client yang sedang menunggu event topic ini
+func HasSubscribers(topic string) bool {
+	subLock.Lock()
+	defer subLock.Unlock()
+
+	for s := range subscribers {
+		if s.wants(topic) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,287 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TopicFocus = "focus"
+
+	DefaultFocusMode = "Do Not Disturb"
+	maxFocusExpiry   = 24 * time.Hour
+)
+
+// Nama shortcut untuk menyalakan/mematikan satu Focus mode (dibuat user di app Shortcuts)
+type FocusShortcuts struct {
+	On  string `json:"on"`
+	Off string `json:"off"`
+}
+
+type FocusState struct {
+	Active   bool     `json:"active"`
+	Mode     string   `json:"mode,omitempty"`
+	ModeID   string   `json:"mode_id,omitempty"`
+	Modes    []string `json:"modes"`
+	Expires  int64    `json:"expires,omitempty"` // Unix milidetik, kapan state sebelumnya dikembalikan
+	Restore  string   `json:"restore,omitempty"` // Mode yang dikembalikan saat expire, kosong = Focus mati
+	Verified bool     `json:"verified"`          // false = database tidak terbaca, Active/Mode hanya yang diminta
+}
+
+type FocusRequest struct {
+	Mode      string `json:"mode"` // Kosong = Do Not Disturb
+	Enabled   bool   `json:"enabled"`
+	ExpiresIn int    `json:"expires_in"` // Detik, 0 = permanen
+}
+
+var (
+	focusShortcuts = map[string]FocusShortcuts{}
+	focusTimer     *time.Timer
+	focusExpires   time.Time
+	focusRestore   string
+	focusLock      sync.Mutex
+)
+
+// LoadFocusShortcuts: mapping mode -> shortcut dari file JSON.
+// Tanpa mapping dipakai shortcut "<Mode> On" dan "<Mode> Off".
+func LoadFocusShortcuts(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var m map[string]FocusShortcuts
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("invalid focus file %s: %v", path, err)
+	}
+
+	focusLock.Lock()
+	focusShortcuts = m
+	focusLock.Unlock()
+	return nil
+}
+
+func focusDBPath(name string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "DoNotDisturb", "DB", name)
+}
+
+// readFocusDB: Focus aktif dari database DoNotDisturb (butuh Full Disk Access).
+// Focus yang aktif karena jadwal/otomatis tidak tercatat di Assertions.json.
+func readFocusDB() (FocusState, error) {
+	var configs struct {
+		Data []struct {
+			ModeConfigurations map[string]struct {
+				Mode struct {
+					Name string `json:"name"`
+				} `json:"mode"`
+			} `json:"modeConfigurations"`
+		} `json:"data"`
+	}
+	data, err := os.ReadFile(focusDBPath("ModeConfigurations.json"))
+	if err != nil {
+		return FocusState{}, fmt.Errorf("%w: cannot read Focus database (Full Disk Access required): %v", ErrUnsupported, err)
+	}
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return FocusState{}, fmt.Errorf("invalid ModeConfigurations.json: %v", err)
+	}
+
+	state := FocusState{Modes: []string{}, Verified: true}
+	names := map[string]string{}
+	for _, d := range configs.Data {
+		for id, c := range d.ModeConfigurations {
+			names[id] = c.Mode.Name
+			state.Modes = append(state.Modes, c.Mode.Name)
+		}
+	}
+	sort.Strings(state.Modes)
+
+	var assertions struct {
+		Data []struct {
+			StoreAssertionRecords []struct {
+				AssertionDetails struct {
+					ModeIdentifier string `json:"assertionDetailsModeIdentifier"`
+				} `json:"assertionDetails"`
+			} `json:"storeAssertionRecords"`
+		} `json:"data"`
+	}
+	data, err = os.ReadFile(focusDBPath("Assertions.json"))
+	if err != nil {
+		return FocusState{}, fmt.Errorf("%w: cannot read Focus database (Full Disk Access required): %v", ErrUnsupported, err)
+	}
+	if err := json.Unmarshal(data, &assertions); err != nil {
+		return FocusState{}, fmt.Errorf("invalid Assertions.json: %v", err)
+	}
+
+	for _, d := range assertions.Data {
+		for _, rec := range d.StoreAssertionRecords {
+			state.Active = true
+			state.ModeID = rec.AssertionDetails.ModeIdentifier
+			state.Mode = names[state.ModeID]
+		}
+	}
+	return state, nil
+}
+
+// GetFocusState: Focus aktif ditambah info expiry dari SetFocus
+func GetFocusState() (FocusState, error) {
+	state, err := readFocusDB()
+	if err != nil {
+		return FocusState{}, err
+	}
+
+	focusLock.Lock()
+	defer focusLock.Unlock()
+	if focusTimer != nil {
+		state.Expires = focusExpires.UnixMilli()
+		state.Restore = focusRestore
+	}
+	return state, nil
+}
+
+func focusShortcutFor(mode string, enabled bool) string {
+	focusLock.Lock()
+	s, ok := focusShortcuts[mode]
+	focusLock.Unlock()
+
+	switch {
+	case ok && enabled && s.On != "":
+		return s.On
+	case ok && !enabled && s.Off != "":
+		return s.Off
+	case enabled:
+		return mode + " On"
+	default:
+		return mode + " Off"
+	}
+}
+
+// runFocusShortcut: jalankan shortcut lewat CLI "shortcuts", ErrUnsupported jika belum dibuat
+func runFocusShortcut(mode string, enabled bool) error {
+	name := focusShortcutFor(mode, enabled)
+
+	out, err := exec.Command("shortcuts", "list").Output()
+	if err != nil {
+		return fmt.Errorf("%w: shortcuts CLI not available", ErrUnsupported)
+	}
+	found := false
+	for _, line := range strings.Split(string(out), "\n") {
+		if strings.TrimSpace(line) == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: shortcut %q not found, create it in the Shortcuts app with a Set Focus action", ErrUnsupported, name)
+	}
+
+	if out, err := exec.Command("shortcuts", "run", name).CombinedOutput(); err != nil {
+		return fmt.Errorf("shortcut %q failed: %s", name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// SetFocus: nyalakan/matikan Focus. Dengan ExpiresIn, state sebelumnya dikembalikan setelah waktu habis.
+func SetFocus(req FocusRequest) (FocusState, error) {
+	if req.Mode == "" {
+		req.Mode = DefaultFocusMode
+	}
+	expiry := time.Duration(req.ExpiresIn) * time.Second
+	if expiry < 0 || expiry > maxFocusExpiry {
+		return FocusState{}, fmt.Errorf("%w: expires_in must be between 0 and %d seconds", ErrInvalidValue, int(maxFocusExpiry.Seconds()))
+	}
+
+	// State sebelumnya; tanpa Full Disk Access dianggap kebalikan dari aksi ini
+	restore := ""
+	if prev, err := readFocusDB(); err == nil {
+		if prev.Active {
+			restore = prev.Mode
+		}
+	} else if !req.Enabled {
+		restore = req.Mode
+	}
+
+	focusLock.Lock()
+	if focusTimer != nil {
+		// Masih ada expiry sebelumnya: tetap kembali ke state asli sebelum expiry pertama
+		restore = focusRestore
+	}
+	focusLock.Unlock()
+
+	// Timer lama baru diganti setelah shortcut berhasil, supaya restore tidak hilang jika gagal
+	if err := runFocusShortcut(req.Mode, req.Enabled); err != nil {
+		return FocusState{}, err
+	}
+
+	focusLock.Lock()
+	if focusTimer != nil {
+		focusTimer.Stop()
+		focusTimer = nil
+	}
+	if expiry > 0 {
+		focusRestore = restore
+		focusExpires = time.Now().Add(expiry)
+		current := req.Mode
+		if !req.Enabled {
+			current = ""
+		}
+		expires := focusExpires
+		focusTimer = time.AfterFunc(expiry, func() { restoreFocus(expires, current, restore) })
+	}
+	focusLock.Unlock()
+
+	if !req.Enabled {
+		return publishFocus(false, "")
+	}
+	return publishFocus(true, req.Mode)
+}
+
+// restoreFocus: dipanggil saat expiry habis, diabaikan jika sudah diganti SetFocus baru
+func restoreFocus(expires time.Time, current, restore string) {
+	focusLock.Lock()
+	if focusTimer == nil || !focusExpires.Equal(expires) {
+		focusLock.Unlock()
+		return
+	}
+	focusTimer = nil
+	focusLock.Unlock()
+
+	var err error
+	switch {
+	case restore != "":
+		err = runFocusShortcut(restore, true)
+	case current != "":
+		err = runFocusShortcut(current, false)
+	}
+	if err != nil {
+		PublishEvent(TopicFocus, "error", map[string]string{"error": err.Error()})
+		return
+	}
+	publishFocus(restore != "", restore)
+}
+
+// publishFocus: state setelah shortcut jalan. Jika database tidak bisa dibaca, kirim state yang diminta
+func publishFocus(active bool, mode string) (FocusState, error) {
+	state, err := GetFocusState()
+	if err != nil {
+		focusLock.Lock()
+		state = FocusState{Active: active, Mode: mode, Modes: []string{}}
+		if focusTimer != nil {
+			state.Expires = focusExpires.UnixMilli()
+			state.Restore = focusRestore
+		}
+		focusLock.Unlock()
+	}
+	PublishEvent(TopicFocus, "change", state)
+	return state, nil
+}
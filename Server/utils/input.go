@@ -0,0 +1,231 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf16"
+)
+
+// InputEvent: satu event dari client keyboard/trackpad.
+//
+//	{"type":"move","dx":4,"dy":-2}
+//	{"type":"click","button":"left","count":2}
+//	{"type":"down","button":"left"} / {"type":"up","button":"left"}
+//	{"type":"drag","dx":4,"dy":0}  (tombol ditahan otomatis sampai event non-drag berikutnya)
+//	{"type":"scroll","dx":0,"dy":-40}
+//	{"type":"key","key":"c","modifiers":["cmd"]}
+//	{"type":"text","text":"héllo 👋"}
+type InputEvent struct {
+	Type      string   `json:"type"`
+	DX        float64  `json:"dx"`
+	DY        float64  `json:"dy"`
+	Button    string   `json:"button"` // left (default), right, middle
+	Count     int      `json:"count"`  // 2 = double click
+	Key       string   `json:"key"`
+	Modifiers []string `json:"modifiers"` // cmd, shift, alt/option, ctrl, fn
+	Text      string   `json:"text"`
+}
+
+// Mouse event kind untuk inputMouse (platform)
+const (
+	mouseMove = iota
+	mouseDown
+	mouseUp
+	mouseDrag
+)
+
+// CGEventFlags
+var modifierFlags = map[string]uint64{
+	"shift":  0x20000,
+	"ctrl":   0x40000,
+	"alt":    0x80000,
+	"option": 0x80000,
+	"cmd":    0x100000,
+	"fn":     0x800000,
+}
+
+var mouseButtons = map[string]int{"": 0, "left": 0, "right": 1, "middle": 2}
+
+// Virtual key code macOS (layout ANSI)
+var keyCodes = map[string]int{
+	"a": 0x00, "s": 0x01, "d": 0x02, "f": 0x03, "h": 0x04, "g": 0x05, "z": 0x06, "x": 0x07,
+	"c": 0x08, "v": 0x09, "b": 0x0B, "q": 0x0C, "w": 0x0D, "e": 0x0E, "r": 0x0F, "y": 0x10,
+	"t": 0x11, "1": 0x12, "2": 0x13, "3": 0x14, "4": 0x15, "6": 0x16, "5": 0x17, "=": 0x18,
+	"9": 0x19, "7": 0x1A, "-": 0x1B, "8": 0x1C, "0": 0x1D, "]": 0x1E, "o": 0x1F, "u": 0x20,
+	"[": 0x21, "i": 0x22, "p": 0x23, "l": 0x25, "j": 0x26, "'": 0x27, "k": 0x28, ";": 0x29,
+	"\\": 0x2A, ",": 0x2B, "/": 0x2C, "n": 0x2D, "m": 0x2E, ".": 0x2F, "`": 0x32,
+
+	"return": 0x24, "enter": 0x24, "tab": 0x30, "space": 0x31, "delete": 0x33, "backspace": 0x33,
+	"escape": 0x35, "esc": 0x35, "forwarddelete": 0x75, "home": 0x73, "end": 0x77,
+	"pageup": 0x74, "pagedown": 0x79, "left": 0x7B, "right": 0x7C, "down": 0x7D, "up": 0x7E,
+
+	"f1": 0x7A, "f2": 0x78, "f3": 0x63, "f4": 0x76, "f5": 0x60, "f6": 0x61,
+	"f7": 0x62, "f8": 0x64, "f9": 0x65, "f10": 0x6D, "f11": 0x67, "f12": 0x6F,
+}
+
+// InputSession: state satu koneksi (tombol yang ditahan, gerakan mouse yang belum dikirim).
+// Event move/drag dikumpulkan dan baru dikirim saat Flush, supaya banjir event dari
+// trackpad HP tidak menjadi ratusan CGEvent per detik.
+type InputSession struct {
+	mu       sync.Mutex
+	dx, dy   float64
+	held     map[int]bool
+	dragging int // Tombol yang ditekan otomatis oleh event "drag", -1 jika tidak ada
+}
+
+func NewInputSession() *InputSession {
+	return &InputSession{held: map[int]bool{}, dragging: -1}
+}
+
+// Handle: proses satu event. Move/drag ditunda sampai Flush
+func (s *InputSession) Handle(ev InputEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	button, ok := mouseButtons[ev.Button]
+	if !ok {
+		return fmt.Errorf("%w: unknown button: %s", ErrInvalidValue, ev.Button)
+	}
+
+	switch ev.Type {
+	case "move":
+		s.dx += ev.DX
+		s.dy += ev.DY
+		return nil
+	case "drag":
+		if s.dragging < 0 && !s.held[button] {
+			if err := s.flushLocked(); err != nil {
+				return err
+			}
+			if err := inputMouse(mouseDown, button, 1); err != nil {
+				return err
+			}
+			s.held[button] = true
+			s.dragging = button
+		}
+		s.dx += ev.DX
+		s.dy += ev.DY
+		return nil
+	}
+
+	// Event lain: kirim gerakan yang tertunda dulu supaya urutannya benar
+	if err := s.flushLocked(); err != nil {
+		return err
+	}
+	if s.dragging >= 0 && !(ev.Type == "up" && button == s.dragging) {
+		if err := s.releaseDragLocked(); err != nil {
+			return err
+		}
+	}
+
+	switch ev.Type {
+	case "click":
+		count := max(ev.Count, 1)
+		if err := inputMouse(mouseDown, button, count); err != nil {
+			return err
+		}
+		return inputMouse(mouseUp, button, count)
+	case "down":
+		s.held[button] = true
+		return inputMouse(mouseDown, button, 1)
+	case "up":
+		delete(s.held, button)
+		if s.dragging == button {
+			s.dragging = -1
+		}
+		return inputMouse(mouseUp, button, 1)
+	case "scroll":
+		return inputScroll(int(ev.DX), int(ev.DY))
+	case "key":
+		return pressKey(ev.Key, ev.Modifiers)
+	case "text":
+		return typeText(ev.Text)
+	default:
+		return fmt.Errorf("%w: unknown input type: %s", ErrInvalidValue, ev.Type)
+	}
+}
+
+// Flush: kirim gerakan mouse yang terkumpul (dipanggil berkala oleh handler)
+func (s *InputSession) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flushLocked()
+}
+
+// Close: lepas semua tombol supaya mouse tidak "nyangkut" saat koneksi putus
+func (s *InputSession) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flushLocked()
+	for button := range s.held {
+		inputMouse(mouseUp, button, 1)
+	}
+	s.held = map[int]bool{}
+	s.dragging = -1
+}
+
+func (s *InputSession) flushLocked() error {
+	if s.dx == 0 && s.dy == 0 {
+		return nil
+	}
+	dx, dy := s.dx, s.dy
+	s.dx, s.dy = 0, 0
+
+	// Saat tombol ditahan macOS butuh event "dragged", bukan "moved"
+	kind, button := mouseMove, 0
+	for b := range s.held {
+		kind, button = mouseDrag, b
+		break
+	}
+	return inputMoveRelative(dx, dy, kind, button)
+}
+
+func (s *InputSession) releaseDragLocked() error {
+	button := s.dragging
+	s.dragging = -1
+	delete(s.held, button)
+	return inputMouse(mouseUp, button, 1)
+}
+
+func pressKey(key string, modifiers []string) error {
+	code, ok := keyCodes[strings.ToLower(key)]
+	if !ok {
+		return fmt.Errorf("%w: unknown key: %s", ErrInvalidValue, key)
+	}
+
+	var flags uint64
+	for _, m := range modifiers {
+		f, ok := modifierFlags[strings.ToLower(m)]
+		if !ok {
+			return fmt.Errorf("%w: unknown modifier: %s", ErrInvalidValue, m)
+		}
+		flags |= f
+	}
+
+	if err := inputKey(code, true, flags); err != nil {
+		return err
+	}
+	return inputKey(code, false, flags)
+}
+
+// typeText: kirim sebagai string unicode (bukan key code), jadi aman untuk emoji dan huruf non-ASCII
+// di layout keyboard apa pun. CGEvent hanya menerima 20 code unit UTF-16 per event.
+func typeText(text string) error {
+	var chunk []uint16
+	for _, r := range text {
+		units := utf16.Encode([]rune{r})
+		if len(chunk)+len(units) > 20 {
+			if err := inputUnicode(chunk); err != nil {
+				return err
+			}
+			chunk = nil
+		}
+		chunk = append(chunk, units...)
+	}
+	if len(chunk) > 0 {
+		return inputUnicode(chunk)
+	}
+	return nil
+}
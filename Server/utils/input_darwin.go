@@ -0,0 +1,152 @@
+//go:build darwin && cgo
+
+package utils
+
+/*
+#cgo LDFLAGS: -framework ApplicationServices
+#include <ApplicationServices/ApplicationServices.h>
+
+static CGPoint currentMouse() {
+	CGEventRef e = CGEventCreate(NULL);
+	CGPoint p = CGEventGetLocation(e);
+	CFRelease(e);
+	return p;
+}
+
+// Gabungan bounds semua display aktif
+static CGRect desktopBounds() {
+	CGDirectDisplayID ids[16];
+	uint32_t n = 0;
+	CGGetActiveDisplayList(16, ids, &n);
+	CGRect r = CGRectNull;
+	for (uint32_t i = 0; i < n; i++) {
+		r = CGRectUnion(r, CGDisplayBounds(ids[i]));
+	}
+	return r;
+}
+
+// kind: 0 move, 1 down, 2 up, 3 drag. button: 0 left, 1 right, 2 middle
+static void postMouse(int kind, double x, double y, int button, int clicks) {
+	CGEventType type;
+	switch (kind) {
+	case 1:
+		type = button == 0 ? kCGEventLeftMouseDown : button == 1 ? kCGEventRightMouseDown : kCGEventOtherMouseDown;
+		break;
+	case 2:
+		type = button == 0 ? kCGEventLeftMouseUp : button == 1 ? kCGEventRightMouseUp : kCGEventOtherMouseUp;
+		break;
+	case 3:
+		type = button == 0 ? kCGEventLeftMouseDragged : button == 1 ? kCGEventRightMouseDragged : kCGEventOtherMouseDragged;
+		break;
+	default:
+		type = kCGEventMouseMoved;
+	}
+
+	CGEventRef e = CGEventCreateMouseEvent(NULL, type, CGPointMake(x, y), (CGMouseButton)button);
+	if (clicks > 1) {
+		CGEventSetIntegerValueField(e, kCGMouseEventClickState, clicks);
+	}
+	CGEventPost(kCGHIDEventTap, e);
+	CFRelease(e);
+}
+
+static void postScroll(int dx, int dy) {
+	CGEventRef e = CGEventCreateScrollWheelEvent(NULL, kCGScrollEventUnitPixel, 2, dy, dx);
+	CGEventPost(kCGHIDEventTap, e);
+	CFRelease(e);
+}
+
+static void postKey(int code, int down, unsigned long long flags) {
+	CGEventRef e = CGEventCreateKeyboardEvent(NULL, (CGKeyCode)code, down ? true : false);
+	CGEventSetFlags(e, (CGEventFlags)flags);
+	CGEventPost(kCGHIDEventTap, e);
+	CFRelease(e);
+}
+
+static void postUnicode(const UniChar *chars, int n) {
+	CGEventRef down = CGEventCreateKeyboardEvent(NULL, 0, true);
+	CGEventKeyboardSetUnicodeString(down, n, chars);
+	CGEventPost(kCGHIDEventTap, down);
+	CFRelease(down);
+
+	CGEventRef up = CGEventCreateKeyboardEvent(NULL, 0, false);
+	CGEventKeyboardSetUnicodeString(up, n, chars);
+	CGEventPost(kCGHIDEventTap, up);
+	CFRelease(up);
+}
+
+static int trusted() {
+	return AXIsProcessTrusted() ? 1 : 0;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// CGEventPost diam-diam diabaikan tanpa izin Accessibility, jadi dicek dulu
+func checkInputAccess() error {
+	if C.trusted() == 0 {
+		return fmt.Errorf("%w: grant Accessibility permission to the agent in System Settings > Privacy & Security", ErrUnsupported)
+	}
+	return nil
+}
+
+func inputMoveRelative(dx, dy float64, kind, button int) error {
+	if err := checkInputAccess(); err != nil {
+		return err
+	}
+
+	p := C.currentMouse()
+	b := C.desktopBounds()
+
+	x := min(max(float64(p.x)+dx, float64(b.origin.x)), float64(b.origin.x+b.size.width)-1)
+	y := min(max(float64(p.y)+dy, float64(b.origin.y)), float64(b.origin.y+b.size.height)-1)
+
+	C.postMouse(C.int(kind), C.double(x), C.double(y), C.int(button), 1)
+	return nil
+}
+
+func inputMouse(kind, button, clicks int) error {
+	if err := checkInputAccess(); err != nil {
+		return err
+	}
+
+	p := C.currentMouse()
+	C.postMouse(C.int(kind), C.double(p.x), C.double(p.y), C.int(button), C.int(clicks))
+	return nil
+}
+
+func inputScroll(dx, dy int) error {
+	if err := checkInputAccess(); err != nil {
+		return err
+	}
+	C.postScroll(C.int(dx), C.int(dy))
+	return nil
+}
+
+func inputKey(code int, down bool, flags uint64) error {
+	if err := checkInputAccess(); err != nil {
+		return err
+	}
+
+	d := 0
+	if down {
+		d = 1
+	}
+	C.postKey(C.int(code), C.int(d), C.ulonglong(flags))
+	return nil
+}
+
+func inputUnicode(chars []uint16) error {
+	if err := checkInputAccess(); err != nil {
+		return err
+	}
+	if len(chars) == 0 {
+		return nil
+	}
+	C.postUnicode((*C.UniChar)(unsafe.Pointer(&chars[0])), C.int(len(chars)))
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !darwin || !cgo
+
+package utils
+
+import "fmt"
+
+var errNoInput = fmt.Errorf("%w: input injection requires macOS and a cgo build", ErrUnsupported)
+
+func inputMoveRelative(dx, dy float64, kind, button int) error { return errNoInput }
+
+func inputMouse(kind, button, clicks int) error { return errNoInput }
+
+func inputScroll(dx, dy int) error { return errNoInput }
+
+func inputKey(code int, down bool, flags uint64) error { return errNoInput }
+
+func inputUnicode(chars []uint16) error { return errNoInput }
@@ -0,0 +1,154 @@
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type MediaCommand struct {
+	Player string  `json:"player"` // "Spotify", "Music" atau bundle ID. Kosong = player yang sedang aktif
+	Action string  `json:"action"` // play, pause, playpause, next, prev, seek, skip, shuffle, repeat, volume, like
+	Value  float64 `json:"value"`  // seek: posisi (detik), skip: +/- detik, volume: 0-100
+}
+
+// Player yang bisa dikontrol langsung lewat AppleScript dictionary-nya
+var scriptablePlayers = map[string]string{
+	"com.spotify.client": "Spotify",
+	"com.apple.Music":    "Music",
+}
+
+// SendMediaCommand: kirim perintah langsung ke player (bukan simulasi tombol keyboard)
+func SendMediaCommand(cmd MediaCommand) (MediaState, error) {
+	player := resolvePlayer(cmd.Player)
+
+	var err error
+	if player != "" {
+		err = scriptedMediaCommand(player, cmd)
+	} else {
+		err = systemMediaCommand(cmd)
+	}
+	if err != nil {
+		return MediaState{}, err
+	}
+
+	// Refresh cache sekaligus publish event, jadi client lain ikut ter-update
+	return refreshMedia(), nil
+}
+
+// resolvePlayer: nama app scriptable, atau "" jika harus lewat now-playing sistem
+func resolvePlayer(player string) string {
+	if player == "" {
+		player = GetCachedMediaInfo().BundleID
+	}
+	if name, ok := scriptablePlayers[player]; ok {
+		return name
+	}
+	for _, name := range scriptablePlayers {
+		if strings.EqualFold(name, player) {
+			return name
+		}
+	}
+	return ""
+}
+
+func scriptedMediaCommand(player string, cmd MediaCommand) error {
+	var script string
+
+	switch cmd.Action {
+	case "play", "pause", "playpause":
+		script = cmd.Action
+	case "next":
+		script = "next track"
+	case "prev":
+		script = "previous track"
+	case "seek":
+		if cmd.Value < 0 {
+			return fmt.Errorf("%w: position must not be negative", ErrInvalidValue)
+		}
+		script = fmt.Sprintf("set player position to %s", formatNum(cmd.Value))
+	case "skip":
+		script = fmt.Sprintf("set player position to (player position + (%s))", formatNum(cmd.Value))
+	case "volume":
+		if err := checkVolume(int(cmd.Value)); err != nil {
+			return err
+		}
+		script = fmt.Sprintf("set sound volume to %d", int(cmd.Value))
+	case "shuffle":
+		if player == "Spotify" {
+			script = "set shuffling to not shuffling"
+		} else {
+			script = "set shuffle enabled to not shuffle enabled"
+		}
+	case "repeat":
+		if player == "Spotify" {
+			script = "set repeating to not repeating"
+		} else {
+			// Music: off -> all -> one -> off
+			script = `
+				if song repeat is off then
+					set song repeat to all
+				else if song repeat is all then
+					set song repeat to one
+				else
+					set song repeat to off
+				end if`
+		}
+	case "like":
+		if player == "Spotify" {
+			return fmt.Errorf("%w: Spotify does not expose liking tracks to AppleScript", ErrUnsupported)
+		}
+		// "favorited" sejak macOS 14, sebelumnya "loved"
+		script = `
+			try
+				set favorited of current track to true
+			on error
+				set loved of current track to true
+			end try`
+	default:
+		return fmt.Errorf("%w: unknown media action: %s", ErrInvalidValue, cmd.Action)
+	}
+
+	return runOsa(fmt.Sprintf("tell application %q\n%s\nend tell", player, script))
+}
+
+// systemMediaCommand: untuk player non-scriptable (browser, podcast, VLC, ...) lewat media-control
+func systemMediaCommand(cmd MediaCommand) error {
+	var args []string
+
+	switch cmd.Action {
+	case "play", "pause":
+		args = []string{cmd.Action}
+	case "playpause":
+		args = []string{"toggle-play-pause"}
+	case "next":
+		args = []string{"next-track"}
+	case "prev":
+		args = []string{"previous-track"}
+	case "seek":
+		if cmd.Value < 0 {
+			return fmt.Errorf("%w: position must not be negative", ErrInvalidValue)
+		}
+		args = []string{"seek", formatNum(cmd.Value)}
+	case "skip":
+		cur := GetCachedMediaInfo()
+		pos := cur.Position + float64(time.Now().UnixMilli()-cur.Timestamp)/1000*cur.Rate
+		args = []string{"seek", formatNum(max(0, pos+cmd.Value))}
+	case "shuffle", "repeat", "volume", "like":
+		return fmt.Errorf("%w: %s is only available for Spotify and Music", ErrUnsupported, cmd.Action)
+	default:
+		return fmt.Errorf("%w: unknown media action: %s", ErrInvalidValue, cmd.Action)
+	}
+
+	out, err := exec.Command("media-control", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("media-control error: %w %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func formatNum(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -1,78 +1,189 @@
 package utils
 
 import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"os/exec"
-	"strconv"
-	"strings"
+	"sync"
+	"time"
 )
 
 type MediaState struct {
-	Player   string  `json:"player"` // "Spotify" atau "Music"
-	State    string  `json:"state"`  // "playing", "paused", "stopped"
-	Title    string  `json:"title"`
-	Artist   string  `json:"artist"`
-	Position float64 `json:"position"` // Posisi sekarang (detik)
-	Duration float64 `json:"duration"` // Total durasi (detik)
+	Player     string  `json:"player"`    // Nama app, mis. "Spotify", "Music", "Safari"
+	BundleID   string  `json:"bundle_id"` // Bundle ID app sumber
+	State      string  `json:"state"`     // "playing", "paused", "stopped"
+	Title      string  `json:"title"`
+	Artist     string  `json:"artist"`
+	Album      string  `json:"album"`
+	Position   float64 `json:"position"`  // Posisi (detik) pada Timestamp
+	Duration   float64 `json:"duration"`  // Total durasi (detik)
+	Rate       float64 `json:"rate"`      // Kecepatan playback, 0 saat pause
+	Timestamp  int64   `json:"timestamp"` // Unix milidetik saat Position diambil
+	ArtworkURL string  `json:"artwork_url,omitempty"`
 }
 
+// Output "media-control get" (https://github.com/ungive/media-control)
+type nowPlayingInfo struct {
+	BundleID        string    `json:"bundleIdentifier"`
+	Playing         bool      `json:"playing"`
+	Title           string    `json:"title"`
+	Artist          string    `json:"artist"`
+	Album           string    `json:"album"`
+	Duration        float64   `json:"duration"`
+	ElapsedTime     float64   `json:"elapsedTime"`
+	PlaybackRate    float64   `json:"playbackRate"`
+	Timestamp       time.Time `json:"timestamp"`
+	ArtworkMimeType string    `json:"artworkMimeType"`
+	ArtworkData     string    `json:"artworkData"` // base64
+}
+
+var (
+	artwork     []byte
+	artworkMime string
+	artworkID   string
+	artworkLock sync.Mutex
+)
+
+// GetMediaInfo: info now-playing dari semua app yang mempublish ke sistem (browser, podcast, VLC, ...).
+// Jika media-control tidak tersedia, fallback ke AppleScript Spotify/Music.
 func GetMediaInfo() MediaState {
-	// Script ini mengecek Spotify dulu, kalau tidak ada baru cek Music
-	script := `
-	tell application "System Events"
-		set spotifyRunning to (name of processes) contains "Spotify"
-		set musicRunning to (name of processes) contains "Music"
-	end tell
-
-	if spotifyRunning then
-		tell application "Spotify"
-			if player state is playing or player state is paused then
-				set trkName to name of current track
-				set trkArtist to artist of current track
-				set trkDur to duration of current track -- Spotify dalam MS
-				set trkPos to player position -- Spotify dalam Detik
-				set pState to player state
-				return "Spotify|" & pState & "|" & trkName & "|" & trkArtist & "|" & trkPos & "|" & (trkDur / 1000)
-			end if
-		end tell
-	end if
-
-	if musicRunning then
-		tell application "Music"
-			if player state is playing or player state is paused then
-				set trkName to name of current track
-				set trkArtist to artist of current track
-				set trkDur to duration of current track -- Music dalam Detik
-				set trkPos to player position
-				set pState to player state
-				return "Music|" & pState & "|" & trkName & "|" & trkArtist & "|" & trkPos & "|" & trkDur
-			end if
-		end tell
-	end if
-
-	return "Stopped|stopped|No Music|-|-|0|0"
-	`
-
-	cmd := exec.Command("/usr/bin/osascript", "-e", script)
-	output, err := cmd.CombinedOutput()
+	if state, ok := systemNowPlaying(); ok {
+		return state
+	}
+	if state, ok := scriptedNowPlaying(); ok {
+		return state
+	}
+	return stoppedMedia()
+}
+
+func stoppedMedia() MediaState {
+	return MediaState{State: "stopped", Title: "No Media", Timestamp: time.Now().UnixMilli()}
+}
+
+func systemNowPlaying() (MediaState, bool) {
+	out, err := exec.Command("media-control", "get").Output()
+	if err != nil {
+		return MediaState{}, false
+	}
+
+	var info *nowPlayingInfo
+	if json.Unmarshal(out, &info) != nil {
+		return MediaState{}, false
+	}
+	if info == nil || info.Title == "" {
+		// "null" = tidak ada yang sedang diputar
+		return stoppedMedia(), true
+	}
+
+	state := MediaState{
+		Player:    playerName(info.BundleID),
+		BundleID:  info.BundleID,
+		State:     "paused",
+		Title:     info.Title,
+		Artist:    info.Artist,
+		Album:     info.Album,
+		Position:  info.ElapsedTime,
+		Duration:  info.Duration,
+		Rate:      info.PlaybackRate,
+		Timestamp: info.Timestamp.UnixMilli(),
+	}
+	if info.Playing {
+		state.State = "playing"
+	} else {
+		state.Rate = 0
+	}
+	if info.Timestamp.IsZero() {
+		state.Timestamp = time.Now().UnixMilli()
+	}
+
+	if info.ArtworkData != "" {
+		if data, err := base64.StdEncoding.DecodeString(info.ArtworkData); err == nil {
+			state.ArtworkURL = "/api/media/artwork?id=" + storeArtwork(data, info.ArtworkMimeType)
+		}
+	}
+
+	return state, true
+}
+
+// scriptedNowPlaying: Spotify dulu, kalau tidak ada baru Music. Hasil JSON supaya aman dari karakter apa pun
+func scriptedNowPlaying() (MediaState, bool) {
+	out, err := runJXA(`
+		function info(name, bundleID) {
+			try {
+				const app = Application(name);
+				if (!app.running()) return null;
+				const st = app.playerState();
+				if (st !== "playing" && st !== "paused") return null;
+				const t = app.currentTrack;
+				const spotify = name === "Spotify";
+				return {
+					player: name,
+					bundle_id: bundleID,
+					state: st,
+					title: t.name(),
+					artist: t.artist(),
+					album: t.album(),
+					position: app.playerPosition(),
+					duration: spotify ? t.duration() / 1000 : t.duration(), // Spotify dalam ms
+					rate: st === "playing" ? 1 : 0,
+					artwork_url: spotify ? t.artworkUrl() : "",
+				};
+			} catch (e) {
+				return null;
+			}
+		}
+		JSON.stringify(info("Spotify", "com.spotify.client") || info("Music", "com.apple.Music"));
+	`)
 	if err != nil {
-		return MediaState{State: "stopped", Title: "Not Running"}
+		return MediaState{}, false
 	}
 
-	// Parse Output: "Player|State|Title|Artist|Pos|Dur"
-	parts := strings.Split(strings.TrimSpace(string(output)), "|")
-	if len(parts) < 6 {
-		return MediaState{State: "stopped", Title: "No Media"}
+	var state *MediaState
+	if json.Unmarshal([]byte(out), &state) != nil || state == nil {
+		return MediaState{}, false
 	}
+	state.Timestamp = time.Now().UnixMilli()
+	return *state, true
+}
 
-	pos, _ := strconv.ParseFloat(parts[4], 64)
-	dur, _ := strconv.ParseFloat(parts[5], 64)
+func playerName(bundleID string) string {
+	for _, a := range GetInstalledApps() {
+		if a.BundleID == bundleID {
+			return a.Name
+		}
+	}
+	return bundleID
+}
+
+func storeArtwork(data []byte, mime string) string {
+	sum := sha1.Sum(data)
+	id := hex.EncodeToString(sum[:8])
+
+	artworkLock.Lock()
+	defer artworkLock.Unlock()
+
+	if id != artworkID {
+		artwork = data
+		artworkMime = mime
+		artworkID = id
+	}
+	return id
+}
 
-	return MediaState{
-		Player:   parts[0],
-		State:    parts[1], // "playing" atau "paused"
-		Title:    parts[2],
-		Artist:   parts[3],
-		Position: pos,
-		Duration: dur,
+// GetArtwork: artwork track yang sedang diputar (dari GetMediaInfo terakhir).
+// id kosong = artwork terbaru; id lama tidak ditemukan karena artwork sudah berganti.
+func GetArtwork(id string) ([]byte, string, bool) {
+	artworkLock.Lock()
+	defer artworkLock.Unlock()
+
+	if len(artwork) == 0 || (id != "" && id != artworkID) {
+		return nil, "", false
+	}
+	mime := artworkMime
+	if mime == "" {
+		mime = "image/jpeg"
 	}
+	return artwork, mime, true
 }
@@ -0,0 +1,216 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const searchLimit = 50
+
+type Playlist struct {
+	ID     string  `json:"id"` // Persistent ID Music
+	Name   string  `json:"name"`
+	Tracks int     `json:"tracks"`
+	Time   float64 `json:"duration"` // Detik
+}
+
+type LibraryItem struct {
+	Kind     string  `json:"kind"`     // song, album, artist
+	TrackID  string  `json:"track_id"` // Untuk album/artist: track pertama yang cocok
+	Name     string  `json:"name"`
+	Artist   string  `json:"artist,omitempty"`
+	Album    string  `json:"album,omitempty"`
+	Duration float64 `json:"duration,omitempty"`
+	Tracks   int     `json:"tracks,omitempty"` // Untuk album/artist
+}
+
+type PlayRequest struct {
+	Player     string `json:"player"`
+	PlaylistID string `json:"playlist_id"` // Music
+	TrackID    string `json:"track_id"`    // Music
+	URI        string `json:"uri"`         // Spotify, mis. spotify:playlist:37i9dQZF1DXcBWIGoYBM5M
+}
+
+var (
+	persistentIDRe = regexp.MustCompile(`^[0-9A-F]{16}$`)
+	spotifyURIRe   = regexp.MustCompile(`^spotify:[a-z]+(:[A-Za-z0-9]+)+$`)
+)
+
+// libraryPlayer: hanya Music dan Spotify yang punya library lewat AppleScript
+func libraryPlayer(player string) (string, error) {
+	name := resolvePlayer(player)
+	if name == "" {
+		if player == "" {
+			// Tidak ada yang sedang diputar, pakai Music sebagai default
+			return "Music", nil
+		}
+		return "", fmt.Errorf("%w: %s has no scriptable library", ErrUnsupported, player)
+	}
+	return name, nil
+}
+
+// jsString: literal string JavaScript yang aman untuk disisipkan ke script JXA
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// ListPlaylists: playlist user di Music
+func ListPlaylists(player string) ([]Playlist, error) {
+	name, err := libraryPlayer(player)
+	if err != nil {
+		return nil, err
+	}
+	if name == "Spotify" {
+		return nil, fmt.Errorf("%w: Spotify does not expose playlists to AppleScript, play them by URI instead", ErrUnsupported)
+	}
+
+	out, err := runJXA(`
+		const music = Application("Music");
+		JSON.stringify(music.userPlaylists().map(p => ({
+			id: p.persistentID(),
+			name: p.name(),
+			tracks: p.tracks.length,
+			duration: p.duration(),
+		})));
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	playlists := []Playlist{}
+	if err := json.Unmarshal([]byte(out), &playlists); err != nil {
+		return nil, fmt.Errorf("invalid playlist output: %v", err)
+	}
+	return playlists, nil
+}
+
+// SearchLibrary: cari di library Music. kind = songs, albums, artists
+func SearchLibrary(player, query, kind string) ([]LibraryItem, error) {
+	name, err := libraryPlayer(player)
+	if err != nil {
+		return nil, err
+	}
+	if name == "Spotify" {
+		return nil, fmt.Errorf("%w: Spotify search is not available through AppleScript", ErrUnsupported)
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidValue)
+	}
+	if kind == "" {
+		kind = "songs"
+	}
+	if kind != "songs" && kind != "albums" && kind != "artists" {
+		return nil, fmt.Errorf("%w: type must be songs, albums or artists", ErrInvalidValue)
+	}
+
+	out, err := runJXA(fmt.Sprintf(`
+		const music = Application("Music");
+		const found = music.search(music.libraryPlaylists[0], { for: %s, only: %s }) || [];
+		JSON.stringify(found.slice(0, 500).map(t => ({
+			track_id: t.persistentID(),
+			name: t.name(),
+			artist: t.artist(),
+			album: t.album(),
+			duration: t.duration(),
+		})));
+	`, jsString(query), jsString(kind)))
+	if err != nil {
+		return nil, err
+	}
+
+	var tracks []LibraryItem
+	if err := json.Unmarshal([]byte(out), &tracks); err != nil {
+		return nil, fmt.Errorf("invalid search output: %v", err)
+	}
+
+	return groupLibraryItems(tracks, kind), nil
+}
+
+// groupLibraryItems: hasil search Music selalu berupa track, album/artist dikelompokkan di sini
+func groupLibraryItems(tracks []LibraryItem, kind string) []LibraryItem {
+	items := []LibraryItem{}
+	index := map[string]int{}
+
+	for _, t := range tracks {
+		var key string
+		item := t
+
+		switch kind {
+		case "songs":
+			item.Kind = "song"
+			items = append(items, item)
+			continue
+		case "albums":
+			key = t.Album + "\x00" + t.Artist
+			item = LibraryItem{Kind: "album", TrackID: t.TrackID, Name: t.Album, Artist: t.Artist}
+		case "artists":
+			key = t.Artist
+			item = LibraryItem{Kind: "artist", TrackID: t.TrackID, Name: t.Artist}
+		}
+
+		if i, ok := index[key]; ok {
+			items[i].Tracks++
+			items[i].Duration += t.Duration
+			continue
+		}
+		item.Tracks = 1
+		item.Duration = t.Duration
+		index[key] = len(items)
+		items = append(items, item)
+	}
+
+	if len(items) > searchLimit {
+		items = items[:searchLimit]
+	}
+	return items
+}
+
+// PlayFromLibrary: putar playlist/track (Music) atau URI (Spotify)
+func PlayFromLibrary(req PlayRequest) (MediaState, error) {
+	player := req.Player
+	if player == "" && req.URI != "" {
+		player = "Spotify"
+	}
+	name, err := libraryPlayer(player)
+	if err != nil {
+		return MediaState{}, err
+	}
+
+	var script string
+	switch {
+	case name == "Spotify":
+		if !spotifyURIRe.MatchString(req.URI) {
+			return MediaState{}, fmt.Errorf("%w: a spotify: URI is required", ErrInvalidValue)
+		}
+		script = fmt.Sprintf(`tell application "Spotify" to play track %q`, req.URI)
+	case req.PlaylistID != "":
+		if !persistentIDRe.MatchString(req.PlaylistID) {
+			return MediaState{}, fmt.Errorf("%w: invalid playlist id", ErrInvalidValue)
+		}
+		script = fmt.Sprintf(`tell application "Music" to play (first user playlist whose persistent ID is %q)`, req.PlaylistID)
+	case req.TrackID != "":
+		if !persistentIDRe.MatchString(req.TrackID) {
+			return MediaState{}, fmt.Errorf("%w: invalid track id", ErrInvalidValue)
+		}
+		script = fmt.Sprintf(`tell application "Music" to play (first track of library playlist 1 whose persistent ID is %q)`, req.TrackID)
+	default:
+		return MediaState{}, fmt.Errorf("%w: playlist_id, track_id or uri is required", ErrInvalidValue)
+	}
+
+	if err := runOsa(script); err != nil {
+		return MediaState{}, err
+	}
+	return refreshMedia(), nil
+}
+
+// GetUpNext: Music dan Spotify tidak membuka antrean Up Next lewat AppleScript
+func GetUpNext(player string) error {
+	name, err := libraryPlayer(player)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s does not expose its Up Next queue to AppleScript", ErrUnsupported, name)
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	TopicMedia = "media"
+
+	mediaPollActive = time.Second     // Ada client yang subscribe event media
+	mediaPollIdle   = 5 * time.Second // Tidak ada subscriber, cukup untuk menjaga cache
+	seekThreshold   = 2.0             // Detik selisih dari posisi ekstrapolasi yang dianggap seek
+)
+
+var (
+	mediaCache MediaState
+	mediaLock  sync.Mutex
+)
+
+// StartMediaWatcher: satu-satunya yang memanggil osascript/media-control secara berkala.
+// Client cukup ekstrapolasi position + (now - timestamp) * rate dan menunggu event.
+func StartMediaWatcher() {
+	go func() {
+		for {
+			refreshMedia()
+			if HasSubscribers(TopicMedia) {
+				time.Sleep(mediaPollActive)
+			} else {
+				time.Sleep(mediaPollIdle)
+			}
+		}
+	}()
+}
+
+// GetCachedMediaInfo: state terakhir dari watcher tanpa menjalankan script baru
+func GetCachedMediaInfo() MediaState {
+	mediaLock.Lock()
+	state := mediaCache
+	mediaLock.Unlock()
+
+	if state.Timestamp == 0 {
+		return refreshMedia()
+	}
+	return state
+}
+
+func refreshMedia() MediaState {
+	cur := GetMediaInfo()
+
+	mediaLock.Lock()
+	prev := mediaCache
+	mediaCache = cur
+	mediaLock.Unlock()
+
+	if prev.Timestamp != 0 {
+		if typ := diffMedia(prev, cur); typ != "" {
+			PublishEvent(TopicMedia, typ, cur)
+		}
+	}
+	return cur
+}
+
+// diffMedia: "track", "state", "seek" atau "" jika tidak ada perubahan berarti
+func diffMedia(prev, cur MediaState) string {
+	if prev.Title != cur.Title || prev.Artist != cur.Artist ||
+		prev.Album != cur.Album || prev.BundleID != cur.BundleID {
+		return "track"
+	}
+	if prev.State != cur.State || prev.Rate != cur.Rate {
+		return "state"
+	}
+
+	elapsed := float64(cur.Timestamp-prev.Timestamp) / 1000
+	expected := prev.Position + elapsed*prev.Rate
+	if math.Abs(cur.Position-expected) > seekThreshold {
+		return "seek"
+	}
+	return ""
+}
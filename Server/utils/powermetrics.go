@@ -8,15 +8,44 @@ import (
 )
 
 type PowerMetrics struct {
-	CPU  float64
-	GPU  float64
-	Temp float64
+	CPU   float64
+	GPU   float64
+	Temp  float64
+	Tasks map[int]TaskEnergy // Dari sampler "tasks", key = PID
+}
+
+// TaskEnergy: data tab Energy di Activity Monitor
+type TaskEnergy struct {
+	PID     int     `json:"pid"`
+	Name    string  `json:"name"`
+	CPUms   float64 `json:"cpu_ms"`  // ms CPU per detik
+	Wakeups float64 `json:"wakeups"` // Interrupt wakeups per detik
+	GPUms   float64 `json:"gpu_ms"`  // ms GPU per detik
+	Energy  float64 `json:"energy"`  // Energy impact
+}
+
+// Kolom numerik tabel tasks, urut sesuai output powermetrics
+var taskColumns = []struct {
+	key   string
+	label string
+}{
+	{"id", "ID"},
+	{"cpu", "CPU ms/s"},
+	{"user", "User%"},
+	{"dl_short", "Deadlines"},
+	{"dl_long", "Deadlines"},
+	{"wake_intr", "Wakeups"},
+	{"wake_idle", "Wakeups"},
+	{"gpu", "GPU ms/s"},
+	{"energy", "Energy Impact"},
 }
 
 func readPowerMetrics() PowerMetrics {
 	// Menjalankan powermetrics (Membutuhkan akses SUDO/ROOT saat menjalankan server)
 	out, _ := exec.Command("sudo", "powermetrics",
-		"--samplers", "cpu_power,gpu_power,thermal",
+		"--samplers", "cpu_power,gpu_power,thermal,tasks",
+		"--show-process-energy",
+		"--show-process-gpu",
 		"-n", "1",
 		"-i", "1000", // Sampel selama 1 detik
 	).Output()
@@ -40,10 +69,69 @@ func readPowerMetrics() PowerMetrics {
 	}
 
 	return PowerMetrics{
-		CPU:  calcCPUAverage(e, p),
-		GPU:  gpu,
-		Temp: temp,
+		CPU:   calcCPUAverage(e, p),
+		GPU:   gpu,
+		Temp:  temp,
+		Tasks: parseTasks(s),
+	}
+}
+
+// parseTasks: baca tabel "*** Running tasks ***".
+// Nama proses bisa mengandung spasi, jadi nilai diambil dari kanan sesuai jumlah kolom di header.
+func parseTasks(s string) map[int]TaskEnergy {
+	tasks := map[int]TaskEnergy{}
+
+	start := strings.Index(s, "*** Running tasks ***")
+	if start < 0 {
+		return tasks
 	}
+	lines := strings.Split(s[start:], "\n")
+
+	var cols []string
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "Name") {
+			for _, c := range taskColumns {
+				if strings.Contains(line, c.label) {
+					cols = append(cols, c.key)
+				}
+			}
+			continue
+		}
+		if cols == nil {
+			continue
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "***") {
+			break
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) <= len(cols) {
+			continue
+		}
+
+		name := strings.Join(fields[:len(fields)-len(cols)], " ")
+		if name == "ALL_TASKS" {
+			continue
+		}
+
+		values := map[string]float64{}
+		for i, key := range cols {
+			v, _ := strconv.ParseFloat(fields[len(fields)-len(cols)+i], 64)
+			values[key] = v
+		}
+
+		pid := int(values["id"])
+		tasks[pid] = TaskEnergy{
+			PID:     pid,
+			Name:    name,
+			CPUms:   values["cpu"],
+			Wakeups: values["wake_intr"],
+			GPUms:   values["gpu"],
+			Energy:  values["energy"],
+		}
+	}
+
+	return tasks
 }
 
 // Fungsi helper untuk mengambil angka float dari string menggunakan Regex
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTasks(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[int]TaskEnergy
+	}{
+		{
+			name: "with gpu column",
+			out: `Machine model: Mac14,2
+*** Sampled system activity (Thu Oct 16 15:39:14 2026 +0700) (1003.21ms elapsed) ***
+
+*** Running tasks ***
+
+Name                               ID     CPU ms/s  User%  Deadlines (<2 ms, 2-5 ms)  Wakeups (Intr, Pkg idle)  GPU ms/s  Energy Impact
+WindowServer                       153    45.12     38.44  10.00   2.00               120.50  30.20             12.34     55.67
+Google Chrome Helper (Renderer)    812    20.05     90.00  0.00    0.00               15.00   1.00              0.00      18.20
+ALL_TASKS                          -2     300.00    60.00  12.00   2.00               500.00  80.00             12.34     210.00
+
+**** Processor usage ****
+`,
+			want: map[int]TaskEnergy{
+				153: {PID: 153, Name: "WindowServer", CPUms: 45.12, Wakeups: 120.50, GPUms: 12.34, Energy: 55.67},
+				812: {PID: 812, Name: "Google Chrome Helper (Renderer)", CPUms: 20.05, Wakeups: 15.00, GPUms: 0, Energy: 18.20},
+			},
+		},
+		{
+			// macOS lama tanpa kolom GPU
+			name: "without gpu column",
+			out: `*** Running tasks ***
+
+Name                               ID     CPU ms/s  User%  Deadlines (<2 ms, 2-5 ms)  Wakeups (Intr, Pkg idle)  Energy Impact
+kernel_task                        0      12.00     0.00   0.00    0.00               400.00  10.00             20.50
+`,
+			want: map[int]TaskEnergy{
+				0: {PID: 0, Name: "kernel_task", CPUms: 12.00, Wakeups: 400.00, Energy: 20.50},
+			},
+		},
+		{
+			name: "no tasks sampler",
+			out: `*** Sampled system activity ***
+CPU die temperature: 45.12 C
+`,
+			want: map[int]TaskEnergy{},
+		},
+		{
+			name: "short rows are skipped",
+			out: `*** Running tasks ***
+Name                               ID     CPU ms/s  User%  Deadlines (<2 ms, 2-5 ms)  Wakeups (Intr, Pkg idle)  GPU ms/s  Energy Impact
+broken                             1      2.00
+`,
+			want: map[int]TaskEnergy{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseTasks(tt.out)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTasks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,19 +1,35 @@
 package utils
 
 import (
-	"os"
 	"os/exec"
-	"sort"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 )
 
 type Process struct {
-	PID      int     `json:"pid"`
-	Name     string  `json:"name"`
-	CPU      float64 `json:"cpu"`
-	RAM      float64 `json:"ram"`
-	Category string  `json:"category"`
+	PID       int     `json:"pid"`
+	PPID      int     `json:"ppid"`
+	User      string  `json:"user,omitempty"`
+	Name      string  `json:"name"`
+	CPU       float64 `json:"cpu"`
+	RAM       float64 `json:"ram"`
+	Memory    uint64  `json:"memory"`  // RSS dalam byte
+	Started   int64   `json:"started"` // Unix detik
+	NetRx     float64 `json:"net_rx"`  // byte/detik dari nettop
+	NetTx     float64 `json:"net_tx"`
+	Energy    float64 `json:"energy"`  // Energy impact dari powermetrics
+	Wakeups   float64 `json:"wakeups"` // Wakeups per detik
+	GPUms     float64 `json:"gpu_ms"`  // ms GPU per detik
+	Path      string  `json:"path,omitempty"`
+	App       string  `json:"app,omitempty"`       // Path bundle .app pemilik proses
+	BundleID  string  `json:"bundle_id,omitempty"` // CFBundleIdentifier dari App
+	Category  string  `json:"category"`
+	Rule      string  `json:"rule"` // Nama ClassRule yang menentukan Category
+	Nice      int     `json:"nice"`
+	Suspended bool    `json:"suspended"` // State "T" (SIGSTOP)
 }
 
 var systemProcesses = map[string]bool{
@@ -27,66 +43,118 @@ var systemProcesses = map[string]bool{
 	"loginwindow": true, "UserEventAgent": true,
 }
 
+// GetTopProcesses: proses dengan CPU tertinggi
 func GetTopProcesses(limit int) []Process {
-	cmd := exec.Command("ps", "-Aceo", "pid,pcpu,pmem,comm", "-r")
-	out, err := cmd.Output()
+	page, _ := QueryProcesses(ProcessQuery{Sort: "cpu", Limit: limit})
+	return page.Processes
+}
+
+// ReadProcessTable: semua proses lengkap dengan PPID, RSS dan path executable
+func ReadProcessTable() []Process {
+	// Tanpa -c, kolom comm berisi path lengkap (bisa mengandung spasi, jadi ditaruh terakhir)
+	return parseProcessTable("-A")
+}
+
+// GetProcess: satu proses berdasarkan PID
+func GetProcess(pid int) (Process, bool) {
+	processes := parseProcessTable("-p", strconv.Itoa(pid))
+	if len(processes) == 0 {
+		return Process{}, false
+	}
+	return processes[0], true
+}
+
+func parseProcessTable(selector ...string) []Process {
+	args := append(selector, "-o", "pid,ppid,user,pcpu,pmem,rss,etime,stat,nice,comm")
+	out, err := exec.Command("ps", args...).Output()
 	if err != nil {
 		return []Process{}
 	}
 
 	lines := strings.Split(string(out), "\n")
-	var processes []Process
+	processes := make([]Process, 0, len(lines))
+	now := time.Now().Unix()
+	tasks := GetPowerMetrics().Tasks
 
 	for i := 1; i < len(lines); i++ {
-		if len(processes) >= limit {
-			break
-		}
-
 		fields := strings.Fields(lines[i])
-		if len(fields) < 4 {
+		if len(fields) < 10 {
 			continue
 		}
 
 		pid, _ := strconv.Atoi(fields[0])
-		cpu, _ := strconv.ParseFloat(fields[1], 64)
-		ram, _ := strconv.ParseFloat(fields[2], 64)
-		name := strings.Join(fields[3:], " ")
-
-		category := "User"
-
-		if systemProcesses[name] ||
-			strings.Contains(name, "Helper") ||
-			strings.Contains(name, "Service") ||
-			strings.Contains(name, "Agent") ||
-			strings.Contains(name, "Daemon") {
-			category = "System"
+		ppid, _ := strconv.Atoi(fields[1])
+		cpu, _ := strconv.ParseFloat(fields[3], 64)
+		ram, _ := strconv.ParseFloat(fields[4], 64)
+		rss, _ := strconv.ParseUint(fields[5], 10, 64)
+		nice, _ := strconv.Atoi(fields[8])
+		path := strings.Join(fields[9:], " ")
+		name := filepath.Base(path)
+
+		net, _ := processNetFor(pid)
+		task := tasks[pid]
+
+		proc := Process{
+			PID:       pid,
+			PPID:      ppid,
+			User:      fields[2],
+			Name:      name,
+			CPU:       cpu,
+			RAM:       ram,
+			Memory:    rss * 1024,
+			Started:   now - int64(parsePsDuration(fields[6])),
+			NetRx:     net.RxRate,
+			NetTx:     net.TxRate,
+			Energy:    task.Energy,
+			Wakeups:   task.Wakeups,
+			GPUms:     task.GPUms,
+			Path:      path,
+			App:       appBundleOf(path),
+			Nice:      nice,
+			Suspended: strings.HasPrefix(fields[7], "T"),
 		}
+		proc.BundleID = BundleIDOf(proc.App)
+		proc.Category, proc.Rule = classifyProcess(proc)
 
-		processes = append(processes, Process{
-			PID:      pid,
-			Name:     name,
-			CPU:      cpu,
-			RAM:      ram,
-			Category: category,
-		})
+		processes = append(processes, proc)
 	}
-	sort.SliceStable(processes, func(i, j int) bool {
-		if processes[i].Category == "System" && processes[j].Category == "User" {
-			return true
-		}
-		if processes[i].Category == "User" && processes[j].Category == "System" {
-			return false
-		}
-		return processes[i].CPU > processes[j].CPU
-	})
 
 	return processes
 }
 
-func KillProcess(pid int) error {
-	proc, err := os.FindProcess(pid)
-	if err != nil {
-		return err
+// parsePsDuration: format etime/time ps "[[dd-]hh:]mm:ss[.cc]" ke detik
+func parsePsDuration(s string) float64 {
+	var days float64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		days, _ = strconv.ParseFloat(d, 64)
+		s = rest
+	}
+
+	var secs float64
+	for _, part := range strings.Split(s, ":") {
+		v, _ := strconv.ParseFloat(part, 64)
+		secs = secs*60 + v
 	}
-	return proc.Kill()
+
+	return days*86400 + secs
+}
+
+// appBundleOf: ambil bundle .app terluar dari path executable.
+// ".../Google Chrome.app/Contents/Frameworks/.../Google Chrome Helper.app/..." -> ".../Google Chrome.app"
+func appBundleOf(path string) string {
+	idx := strings.Index(path, ".app/")
+	if idx < 0 {
+		return ""
+	}
+	return path[:idx+len(".app")]
+}
+
+// isMainAppProcess: executable utama bundle (Foo.app/Contents/MacOS/Foo), bukan helper di dalamnya
+func isMainAppProcess(p Process) bool {
+	return strings.Contains(p.Path, ".app/Contents/MacOS/") && strings.Count(p.Path, ".app/") == 1
+}
+
+// KillProcess: SIGKILL langsung lewat ProtectionPolicy (dipakai KillHandler lama)
+func KillProcess(pid int) error {
+	return CheckedSignal(pid, syscall.SIGKILL, false)
 }
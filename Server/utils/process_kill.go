@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const DefaultKillGrace = 5 * time.Second
+
+var signalNames = map[string]syscall.Signal{
+	"TERM": syscall.SIGTERM,
+	"INT":  syscall.SIGINT,
+	"HUP":  syscall.SIGHUP,
+	"KILL": syscall.SIGKILL,
+	"STOP": syscall.SIGSTOP,
+	"CONT": syscall.SIGCONT,
+}
+
+type KillOptions struct {
+	Signal   syscall.Signal
+	Escalate bool          // Kirim TERM dulu, lalu KILL jika belum keluar setelah Grace
+	Grace    time.Duration // Waktu tunggu sebelum eskalasi
+	Override bool          // Lewati proteksi critical/other-user (bukan deny list & agent sendiri)
+}
+
+type KillResult struct {
+	PID       int    `json:"pid"`
+	Name      string `json:"name,omitempty"`
+	Signal    string `json:"signal"`
+	Escalated bool   `json:"escalated"` // true jika TERM tidak cukup dan KILL dikirim
+	Exited    bool   `json:"exited"`
+	Error     string `json:"error,omitempty"`
+	Code      string `json:"code,omitempty"` // Diisi jika ditolak oleh ProtectionPolicy
+}
+
+// ParseSignal: "TERM", "sigterm", "SIGKILL", dst. Kosong = KILL, perilaku lama /kill (force quit)
+func ParseSignal(name string) (syscall.Signal, error) {
+	n := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "SIG")
+	if n == "" {
+		return syscall.SIGKILL, nil
+	}
+	sig, ok := signalNames[n]
+	if !ok {
+		return 0, fmt.Errorf("unsupported signal: %s", name)
+	}
+	return sig, nil
+}
+
+func SignalName(sig syscall.Signal) string {
+	for name, s := range signalNames {
+		if s == sig {
+			return name
+		}
+	}
+	return fmt.Sprintf("%d", int(sig))
+}
+
+func SignalProcess(pid int, sig syscall.Signal) error {
+	if pid <= 0 {
+		return fmt.Errorf("invalid pid: %d", pid)
+	}
+	return syscall.Kill(pid, sig)
+}
+
+// CheckedSignal: seperti SignalProcess tapi melewati ProtectionPolicy dulu
+func CheckedSignal(pid int, sig syscall.Signal, override bool) error {
+	proc, ok := GetProcess(pid)
+	if !ok {
+		return fmt.Errorf("process %d not found", pid)
+	}
+	if perr := CheckKillPolicy(proc, override); perr != nil {
+		return perr
+	}
+	return SignalProcess(pid, sig)
+}
+
+// ProcessAlive: signal 0 hanya mengecek keberadaan proses
+func ProcessAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+func waitExit(pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if !ProcessAlive(pid) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// KillWithOptions: kirim signal dan laporkan apakah proses benar-benar keluar
+func KillWithOptions(pid int, opts KillOptions) KillResult {
+	sig := opts.Signal
+	if opts.Escalate || sig == 0 {
+		sig = syscall.SIGTERM
+	}
+	grace := opts.Grace
+	if grace <= 0 {
+		grace = DefaultKillGrace
+	}
+
+	res := KillResult{PID: pid, Signal: SignalName(sig)}
+
+	var err error
+	switch sig {
+	case syscall.SIGSTOP:
+		// Lewat SuspendProcess supaya tercatat dan di-resume otomatis, tidak beku selamanya
+		_, err = SuspendProcess(pid, 0, opts.Override)
+	case syscall.SIGCONT:
+		err = ResumeProcess(pid)
+	default:
+		err = CheckedSignal(pid, sig, opts.Override)
+	}
+	if err != nil {
+		res.Error = err.Error()
+		var perr *ProtectionError
+		if errors.As(err, &perr) {
+			res.Name = perr.Name
+			res.Code = perr.Code
+		}
+		return res
+	}
+
+	// STOP/CONT tidak mengakhiri proses, tidak perlu ditunggu
+	if sig == syscall.SIGSTOP || sig == syscall.SIGCONT {
+		return res
+	}
+
+	if !opts.Escalate {
+		res.Exited = waitExit(pid, time.Second)
+		return res
+	}
+
+	if waitExit(pid, grace) {
+		res.Exited = true
+		return res
+	}
+
+	res.Escalated = true
+	res.Signal = SignalName(syscall.SIGKILL)
+	if err := SignalProcess(pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		res.Error = err.Error()
+		return res
+	}
+	res.Exited = waitExit(pid, time.Second)
+
+	return res
+}
+
+// KillPIDs: batch kill, dijalankan paralel supaya masa tunggu eskalasi tidak menumpuk
+func KillPIDs(pids []int, opts KillOptions) []KillResult {
+	results := make([]KillResult, len(pids))
+
+	var wg sync.WaitGroup
+	for i, pid := range pids {
+		wg.Add(1)
+		go func(i, pid int) {
+			defer wg.Done()
+			results[i] = KillWithOptions(pid, opts)
+		}(i, pid)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// KillByName: semua proses dengan nama executable yang sama (case-insensitive)
+func KillByName(name string, opts KillOptions) ([]KillResult, error) {
+	var pids []int
+	names := map[int]string{}
+	for _, p := range ReadProcessTable() {
+		if strings.EqualFold(p.Name, name) {
+			pids = append(pids, p.PID)
+			names[p.PID] = p.Name
+		}
+	}
+	if len(pids) == 0 {
+		return nil, fmt.Errorf("no process named %q", name)
+	}
+
+	results := KillPIDs(pids, opts)
+	for i := range results {
+		results[i].Name = names[results[i].PID]
+	}
+	return results, nil
+}
+
+// KillAppGroup: aplikasi beserta semua helper-nya
+func KillAppGroup(app string, opts KillOptions) ([]KillResult, error) {
+	group, ok := FindAppGroup(app)
+	if !ok {
+		return nil, fmt.Errorf("app not found: %s", app)
+	}
+
+	results := KillPIDs(group.PIDs, opts)
+	for i := range results {
+		results[i].Name = group.Name
+	}
+	return results, nil
+}
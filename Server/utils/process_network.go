@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const nettopInterval = 2 * time.Second
+
+type ProcessNet struct {
+	PID       int     `json:"pid"`
+	Name      string  `json:"name"`
+	BytesIn   uint64  `json:"bytes_in"`
+	BytesOut  uint64  `json:"bytes_out"`
+	RxRate    float64 `json:"rx_rate"` // byte/detik
+	TxRate    float64 `json:"tx_rate"`
+	RxRateStr string  `json:"rx_rate_str"`
+	TxRateStr string  `json:"tx_rate_str"`
+}
+
+var (
+	procNet      = map[int]ProcessNet{}
+	procNetCheck time.Time
+	procNetLock  sync.Mutex
+
+	// Isi kolom proses nettop berbentuk "nama.pid"
+	nettopNameRe = regexp.MustCompile(`^(.+)\.(\d+)$`)
+)
+
+// StartNetworkCollector: jalankan nettop berkala dan hitung rate per proses
+func StartNetworkCollector() {
+	go func() {
+		for {
+			readNettop()
+			time.Sleep(nettopInterval)
+		}
+	}()
+}
+
+func readNettop() {
+	out, err := exec.Command("nettop", "-P", "-x", "-L", "1", "-J", "bytes_in,bytes_out").Output()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	current := parseNettop(string(out))
+	if current == nil {
+		return
+	}
+
+	procNetLock.Lock()
+	defer procNetLock.Unlock()
+
+	elapsed := now.Sub(procNetCheck).Seconds()
+	for pid, cur := range current {
+		prev, ok := procNet[pid]
+		if ok && elapsed > 0 && cur.BytesIn >= prev.BytesIn && cur.BytesOut >= prev.BytesOut {
+			cur.RxRate = float64(cur.BytesIn-prev.BytesIn) / elapsed
+			cur.TxRate = float64(cur.BytesOut-prev.BytesOut) / elapsed
+		}
+		cur.RxRateStr = formatBytes(uint64(cur.RxRate)) + "/s"
+		cur.TxRateStr = formatBytes(uint64(cur.TxRate)) + "/s"
+		current[pid] = cur
+	}
+
+	procNet = current
+	procNetCheck = now
+}
+
+// parseNettop: total byte per PID dari output CSV "nettop -L 1", nil jika format tidak dikenal
+func parseNettop(out string) map[int]ProcessNet {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return nil
+	}
+
+	// Header: "time,,bytes_in,bytes_out,". Kolom proses tidak bernama, tepat setelah "time"
+	header := strings.Split(lines[0], ",")
+	timeCol, nameCol, inCol, outCol := -1, -1, -1, -1
+	for i, h := range header {
+		switch strings.TrimSpace(h) {
+		case "time":
+			timeCol = i
+		case "":
+			if nameCol < 0 && timeCol >= 0 && i == timeCol+1 {
+				nameCol = i
+			}
+		case "bytes_in":
+			inCol = i
+		case "bytes_out":
+			outCol = i
+		}
+	}
+	if nameCol < 0 || inCol < 0 || outCol < 0 {
+		return nil
+	}
+
+	current := map[int]ProcessNet{}
+	for _, line := range lines[1:] {
+		cols := strings.Split(line, ",")
+		if len(cols) <= nameCol || len(cols) <= inCol || len(cols) <= outCol {
+			continue
+		}
+
+		m := nettopNameRe.FindStringSubmatch(strings.TrimSpace(cols[nameCol]))
+		if m == nil {
+			continue
+		}
+
+		pid, _ := strconv.Atoi(m[2])
+		bytesIn, _ := strconv.ParseUint(strings.TrimSpace(cols[inCol]), 10, 64)
+		bytesOut, _ := strconv.ParseUint(strings.TrimSpace(cols[outCol]), 10, 64)
+
+		current[pid] = ProcessNet{PID: pid, Name: m[1], BytesIn: bytesIn, BytesOut: bytesOut}
+	}
+	return current
+}
+
+// GetProcessNetwork: pemakaian jaringan per proses, bandwidth terbesar di atas
+func GetProcessNetwork(limit int) []ProcessNet {
+	procNetLock.Lock()
+	list := make([]ProcessNet, 0, len(procNet))
+	for _, n := range procNet {
+		list = append(list, n)
+	}
+	procNetLock.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].RxRate+list[i].TxRate, list[j].RxRate+list[j].TxRate
+		if a != b {
+			return a > b
+		}
+		return list[i].BytesIn+list[i].BytesOut > list[j].BytesIn+list[j].BytesOut
+	})
+
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
+
+func processNetFor(pid int) (ProcessNet, bool) {
+	procNetLock.Lock()
+	defer procNetLock.Unlock()
+	n, ok := procNet[pid]
+	return n, ok
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNettop(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[int]ProcessNet
+	}{
+		{
+			// Kolom time juga berbentuk "xx.angka", tidak boleh dianggap nama.pid
+			name: "timestamp column",
+			out: `time,,bytes_in,bytes_out,
+15:39:14.011498,launchd.1,0,0,
+15:39:14.011498,Google Chrome He.812,1048576,20480,
+15:39:14.011498,com.docker.backend.4417,512,256,
+`,
+			want: map[int]ProcessNet{
+				1:    {PID: 1, Name: "launchd", BytesIn: 0, BytesOut: 0},
+				812:  {PID: 812, Name: "Google Chrome He", BytesIn: 1048576, BytesOut: 20480},
+				4417: {PID: 4417, Name: "com.docker.backend", BytesIn: 512, BytesOut: 256},
+			},
+		},
+		{
+			name: "columns in different order",
+			out: `time,,bytes_out,bytes_in,
+15:39:14.011498,curl.9001,100,2000,
+`,
+			want: map[int]ProcessNet{
+				9001: {PID: 9001, Name: "curl", BytesIn: 2000, BytesOut: 100},
+			},
+		},
+		{
+			name: "rows without pid are skipped",
+			out: `time,,bytes_in,bytes_out,
+15:39:14.011498,,0,0,
+15:39:14.011498,kernel_task,10,10,
+15:39:14.011498,mDNSResponder.233,300,400,
+`,
+			want: map[int]ProcessNet{
+				233: {PID: 233, Name: "mDNSResponder", BytesIn: 300, BytesOut: 400},
+			},
+		},
+		{
+			name: "unknown header",
+			out: `pid,bytes_in,bytes_out
+1,0,0
+`,
+			want: nil,
+		},
+		{
+			name: "empty output",
+			out:  "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseNettop(tt.out)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNettop() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultProcessLimit = 50
+	MaxProcessLimit     = 1000
+)
+
+type ProcessQuery struct {
+	Sort     string // cpu, mem, name, pid, start, net, energy, wakeups, gpu
+	Order    string // asc, desc (default tergantung Sort)
+	Category string
+	Filter   string // substring nama, case-insensitive
+	Regex    string // regex nama
+	User     string
+	Limit    int
+	Offset   int
+}
+
+type ProcessPage struct {
+	Total      int       `json:"total"` // Jumlah proses setelah filter, sebelum pagination
+	Offset     int       `json:"offset"`
+	Limit      int       `json:"limit"`
+	NextOffset int       `json:"next_offset"` // -1 jika sudah halaman terakhir
+	Processes  []Process `json:"processes"`
+}
+
+var processSorters = map[string]func(a, b Process) bool{
+	"cpu":     func(a, b Process) bool { return a.CPU < b.CPU },
+	"mem":     func(a, b Process) bool { return a.Memory < b.Memory },
+	"name":    func(a, b Process) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) },
+	"pid":     func(a, b Process) bool { return a.PID < b.PID },
+	"start":   func(a, b Process) bool { return a.Started < b.Started },
+	"net":     func(a, b Process) bool { return a.NetRx+a.NetTx < b.NetRx+b.NetTx },
+	"energy":  func(a, b Process) bool { return a.Energy < b.Energy },
+	"wakeups": func(a, b Process) bool { return a.Wakeups < b.Wakeups },
+	"gpu":     func(a, b Process) bool { return a.GPUms < b.GPUms },
+}
+
+// QueryProcesses: filter, sort dan pagination di sisi server
+func QueryProcesses(q ProcessQuery) (ProcessPage, error) {
+	return queryProcesses(ReadProcessTable(), q)
+}
+
+func queryProcesses(processes []Process, q ProcessQuery) (ProcessPage, error) {
+	if q.Sort == "" {
+		q.Sort = "cpu"
+	}
+	less, ok := processSorters[q.Sort]
+	if !ok {
+		return ProcessPage{}, fmt.Errorf("unknown sort: %s", q.Sort)
+	}
+
+	// Nama dan PID naik secara default, sisanya dari yang terbesar
+	desc := q.Sort != "name" && q.Sort != "pid"
+	switch q.Order {
+	case "":
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	default:
+		return ProcessPage{}, fmt.Errorf("unknown order: %s", q.Order)
+	}
+
+	var re *regexp.Regexp
+	if q.Regex != "" {
+		var err error
+		if re, err = regexp.Compile(q.Regex); err != nil {
+			return ProcessPage{}, fmt.Errorf("invalid regex: %v", err)
+		}
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = DefaultProcessLimit
+	}
+	q.Limit = min(q.Limit, MaxProcessLimit)
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+
+	filter := strings.ToLower(q.Filter)
+	matched := []Process{}
+	for _, p := range processes {
+		if q.Category != "" && !strings.EqualFold(p.Category, q.Category) {
+			continue
+		}
+		if q.User != "" && p.User != q.User {
+			continue
+		}
+		if filter != "" && !strings.Contains(strings.ToLower(p.Name), filter) {
+			continue
+		}
+		if re != nil && !re.MatchString(p.Name) {
+			continue
+		}
+		matched = append(matched, p)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if desc {
+			return less(matched[j], matched[i])
+		}
+		return less(matched[i], matched[j])
+	})
+
+	page := ProcessPage{
+		Total:      len(matched),
+		Offset:     q.Offset,
+		Limit:      q.Limit,
+		NextOffset: -1,
+		Processes:  []Process{},
+	}
+
+	if q.Offset < len(matched) {
+		end := min(len(matched), q.Offset+q.Limit)
+		if end < len(matched) {
+			page.NextOffset = end
+		}
+		page.Processes = matched[q.Offset:end]
+	}
+
+	return page, nil
+}
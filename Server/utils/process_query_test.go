@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+var queryFixture = []Process{
+	{PID: 10, Name: "Safari", User: "andi", Category: CategoryBrowser, CPU: 12, Memory: 800, Started: 300},
+	{PID: 3, Name: "launchd", User: "root", Category: CategorySystem, CPU: 0.5, Memory: 20, Started: 100},
+	{PID: 42, Name: "node", User: "andi", Category: CategoryDeveloper, CPU: 55, Memory: 400, Started: 500},
+	{PID: 7, Name: "bash", User: "andi", Category: CategoryUser, CPU: 0, Memory: 5, Started: 200},
+	{PID: 99, Name: "Node Helper", User: "andi", Category: CategoryBackground, CPU: 12, Memory: 100, Started: 400},
+}
+
+func pidsOf(processes []Process) []int {
+	pids := []int{}
+	for _, p := range processes {
+		pids = append(pids, p.PID)
+	}
+	return pids
+}
+
+func TestQueryProcessesSort(t *testing.T) {
+	tests := []struct {
+		name string
+		q    ProcessQuery
+		want []int
+	}{
+		{"default cpu desc, stable on ties", ProcessQuery{}, []int{42, 10, 99, 3, 7}},
+		{"cpu asc", ProcessQuery{Sort: "cpu", Order: "asc"}, []int{7, 3, 10, 99, 42}},
+		{"mem desc", ProcessQuery{Sort: "mem"}, []int{10, 42, 99, 3, 7}},
+		{"name asc, case-insensitive", ProcessQuery{Sort: "name"}, []int{7, 3, 42, 99, 10}},
+		{"pid asc", ProcessQuery{Sort: "pid"}, []int{3, 7, 10, 42, 99}},
+		{"start desc", ProcessQuery{Sort: "start"}, []int{42, 99, 10, 7, 3}},
+		{"filter substring", ProcessQuery{Sort: "pid", Filter: "NODE"}, []int{42, 99}},
+		{"regex", ProcessQuery{Sort: "pid", Regex: `^[a-z]+$`}, []int{3, 7, 42}},
+		{"category and user", ProcessQuery{Sort: "pid", Category: "developer", User: "andi"}, []int{42}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := queryProcesses(queryFixture, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pidsOf(page.Processes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryProcessesPagination(t *testing.T) {
+	tests := []struct {
+		name     string
+		q        ProcessQuery
+		want     []int
+		wantNext int
+		wantLim  int
+	}{
+		{"first page", ProcessQuery{Sort: "pid", Limit: 2}, []int{3, 7}, 2, 2},
+		{"middle page", ProcessQuery{Sort: "pid", Limit: 2, Offset: 2}, []int{10, 42}, 4, 2},
+		{"last page", ProcessQuery{Sort: "pid", Limit: 2, Offset: 4}, []int{99}, -1, 2},
+		{"offset past end", ProcessQuery{Sort: "pid", Limit: 2, Offset: 10}, []int{}, -1, 2},
+		{"negative offset", ProcessQuery{Sort: "pid", Limit: 1, Offset: -5}, []int{3}, 1, 1},
+		{"default limit", ProcessQuery{Sort: "pid"}, []int{3, 7, 10, 42, 99}, -1, DefaultProcessLimit},
+		{"huge limit does not overflow", ProcessQuery{Sort: "pid", Limit: int(^uint(0) >> 1), Offset: 1}, []int{7, 10, 42, 99}, -1, MaxProcessLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := queryProcesses(queryFixture, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := pidsOf(page.Processes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("pids = %v, want %v", got, tt.want)
+			}
+			if page.NextOffset != tt.wantNext {
+				t.Errorf("next_offset = %d, want %d", page.NextOffset, tt.wantNext)
+			}
+			if page.Limit != tt.wantLim {
+				t.Errorf("limit = %d, want %d", page.Limit, tt.wantLim)
+			}
+			if page.Total != len(queryFixture) {
+				t.Errorf("total = %d, want %d", page.Total, len(queryFixture))
+			}
+		})
+	}
+}
+
+func TestQueryProcessesErrors(t *testing.T) {
+	for _, q := range []ProcessQuery{
+		{Sort: "bogus"},
+		{Order: "sideways"},
+		{Regex: "("},
+	} {
+		if _, err := queryProcesses(queryFixture, q); err == nil {
+			t.Errorf("queryProcesses(%+v) expected error", q)
+		}
+	}
+}
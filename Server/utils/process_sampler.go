@@ -0,0 +1,179 @@
+package utils
+
+import (
+	"math"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sampleInterval = 5 * time.Second
+	historySize    = 120 // 10 menit dengan interval 5 detik
+	moverWindow    = time.Minute
+)
+
+type ProcessSample struct {
+	TS     int64   `json:"ts"`
+	CPU    float64 `json:"cpu"`    // Persen CPU sebenarnya selama interval
+	Memory uint64  `json:"memory"` // RSS dalam byte
+}
+
+type ProcessHistory struct {
+	PID     int             `json:"pid"`
+	Name    string          `json:"name"`
+	Samples []ProcessSample `json:"samples"`
+}
+
+type ProcessMover struct {
+	PID         int     `json:"pid"`
+	Name        string  `json:"name"`
+	CPU         float64 `json:"cpu"`
+	CPUDelta    float64 `json:"cpu_delta"`
+	Memory      uint64  `json:"memory"`
+	MemoryDelta int64   `json:"memory_delta"`
+}
+
+type processTrack struct {
+	name     string
+	cpuTime  float64 // Akumulasi waktu CPU (detik) pada sampel terakhir
+	lastSeen time.Time
+	samples  []ProcessSample
+}
+
+var (
+	tracks    = map[int]*processTrack{}
+	trackLock sync.Mutex
+)
+
+// StartProcessSampler: hitung CPU per proses dari selisih waktu CPU, bukan rata-rata ps
+func StartProcessSampler() {
+	go func() {
+		for {
+			sampleProcesses()
+			time.Sleep(sampleInterval)
+		}
+	}()
+}
+
+func sampleProcesses() {
+	out, err := exec.Command("ps", "-A", "-o", "pid,time,rss,comm").Output()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	seen := map[int]bool{}
+
+	trackLock.Lock()
+	defer trackLock.Unlock()
+
+	lines := strings.Split(string(out), "\n")
+	for i := 1; i < len(lines); i++ {
+		fields := strings.Fields(lines[i])
+		if len(fields) < 4 {
+			continue
+		}
+
+		pid, _ := strconv.Atoi(fields[0])
+		cpuTime := parsePsDuration(fields[1])
+		rss, _ := strconv.ParseUint(fields[2], 10, 64)
+		name := filepath.Base(strings.Join(fields[3:], " "))
+		seen[pid] = true
+
+		t, ok := tracks[pid]
+		if !ok || t.name != name {
+			// Proses baru (atau PID dipakai ulang): belum ada delta untuk dihitung
+			tracks[pid] = &processTrack{name: name, cpuTime: cpuTime, lastSeen: now}
+			continue
+		}
+
+		elapsed := now.Sub(t.lastSeen).Seconds()
+		cpu := 0.0
+		if elapsed > 0 && cpuTime >= t.cpuTime {
+			cpu = (cpuTime - t.cpuTime) / elapsed * 100
+		}
+
+		t.samples = append(t.samples, ProcessSample{
+			TS:     now.UnixMilli(),
+			CPU:    math.Round(cpu*100) / 100,
+			Memory: rss * 1024,
+		})
+		if len(t.samples) > historySize {
+			t.samples = t.samples[len(t.samples)-historySize:]
+		}
+		t.cpuTime = cpuTime
+		t.lastSeen = now
+	}
+
+	for pid := range tracks {
+		if !seen[pid] {
+			delete(tracks, pid)
+		}
+	}
+}
+
+// GetProcessHistory: riwayat CPU/RAM satu proses
+func GetProcessHistory(pid int) (ProcessHistory, bool) {
+	trackLock.Lock()
+	defer trackLock.Unlock()
+
+	t, ok := tracks[pid]
+	if !ok {
+		return ProcessHistory{}, false
+	}
+
+	return ProcessHistory{
+		PID:     pid,
+		Name:    t.name,
+		Samples: append([]ProcessSample{}, t.samples...),
+	}, true
+}
+
+// GetTopMovers: proses dengan kenaikan terbesar dalam satu menit terakhir (by "cpu" atau "mem")
+func GetTopMovers(by string, limit int) []ProcessMover {
+	trackLock.Lock()
+	movers := []ProcessMover{}
+	cutoff := time.Now().Add(-moverWindow).UnixMilli()
+
+	for pid, t := range tracks {
+		if len(t.samples) < 2 {
+			continue
+		}
+
+		latest := t.samples[len(t.samples)-1]
+		base := t.samples[0]
+		for _, s := range t.samples {
+			if s.TS >= cutoff {
+				base = s
+				break
+			}
+		}
+
+		movers = append(movers, ProcessMover{
+			PID:         pid,
+			Name:        t.name,
+			CPU:         latest.CPU,
+			CPUDelta:    latest.CPU - base.CPU,
+			Memory:      latest.Memory,
+			MemoryDelta: int64(latest.Memory) - int64(base.Memory),
+		})
+	}
+	trackLock.Unlock()
+
+	sort.Slice(movers, func(i, j int) bool {
+		if by == "mem" {
+			return movers[i].MemoryDelta > movers[j].MemoryDelta
+		}
+		return movers[i].CPUDelta > movers[j].CPUDelta
+	})
+
+	if limit > 0 && len(movers) > limit {
+		movers = movers[:limit]
+	}
+	return movers
+}
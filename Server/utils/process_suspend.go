@@ -0,0 +1,238 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Batas otomatis supaya proses tidak beku selamanya jika client lupa resume
+const (
+	DefaultResumeAfter = 30 * time.Minute
+	MaxResumeAfter     = 4 * time.Hour
+)
+
+type SuspendedProcess struct {
+	PID      int    `json:"pid"`
+	Name     string `json:"name"`
+	Started  int64  `json:"started"`   // Unix detik, untuk mengenali PID yang dipakai ulang
+	Since    int64  `json:"since"`     // Unix milidetik
+	ResumeAt int64  `json:"resume_at"` // Unix milidetik
+	timer    *time.Timer
+}
+
+type ProcessActionResult struct {
+	PID   int    `json:"pid"`
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error,omitempty"`
+	Code  string `json:"code,omitempty"` // Diisi jika ditolak oleh ProtectionPolicy
+}
+
+var (
+	suspended     = map[int]*SuspendedProcess{}
+	suspendedFile string
+	suspendedLock sync.Mutex
+)
+
+// LoadSuspended: resume proses yang tertinggal beku dari agent sebelumnya (crash/kill),
+// lalu catat registry ke file ini supaya bisa dipulihkan lagi nanti
+func LoadSuspended(path string) error {
+	suspendedFile = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var list []SuspendedProcess
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("invalid suspended file %s: %v", path, err)
+	}
+	for _, entry := range list {
+		if proc, ok := GetProcess(entry.PID); ok && abs64(proc.Started-entry.Started) <= 2 {
+			SignalProcess(entry.PID, syscall.SIGCONT)
+		}
+	}
+
+	suspendedLock.Lock()
+	defer suspendedLock.Unlock()
+	saveSuspendedLocked()
+	return nil
+}
+
+// ResumeAllSuspended: dipanggil saat agent berhenti, tidak ada proses yang dibiarkan beku
+func ResumeAllSuspended() {
+	suspendedLock.Lock()
+	defer suspendedLock.Unlock()
+
+	for pid, entry := range suspended {
+		entry.timer.Stop()
+		SignalProcess(pid, syscall.SIGCONT)
+		delete(suspended, pid)
+	}
+	saveSuspendedLocked()
+}
+
+func saveSuspendedLocked() {
+	if suspendedFile == "" {
+		return
+	}
+
+	list := make([]SuspendedProcess, 0, len(suspended))
+	for _, entry := range suspended {
+		list = append(list, *entry)
+	}
+	data, _ := json.MarshalIndent(list, "", "  ")
+	if err := os.WriteFile(suspendedFile, data, 0644); err != nil {
+		fmt.Printf("❌ GAGAL simpan daftar suspend: %v\n", err)
+	}
+}
+
+// SuspendProcess: SIGSTOP dengan timer resume otomatis
+func SuspendProcess(pid int, resumeAfter time.Duration, override bool) (SuspendedProcess, error) {
+	if resumeAfter <= 0 {
+		resumeAfter = DefaultResumeAfter
+	}
+	if resumeAfter > MaxResumeAfter {
+		resumeAfter = MaxResumeAfter
+	}
+
+	proc, ok := GetProcess(pid)
+	if !ok {
+		return SuspendedProcess{}, fmt.Errorf("process %d not found", pid)
+	}
+	if err := CheckedSignal(pid, syscall.SIGSTOP, override); err != nil {
+		return SuspendedProcess{}, err
+	}
+
+	now := time.Now()
+
+	suspendedLock.Lock()
+	defer suspendedLock.Unlock()
+
+	if old, ok := suspended[pid]; ok {
+		old.timer.Stop()
+	}
+
+	entry := &SuspendedProcess{
+		PID:      pid,
+		Name:     proc.Name,
+		Started:  proc.Started,
+		Since:    now.UnixMilli(),
+		ResumeAt: now.Add(resumeAfter).UnixMilli(),
+	}
+	entry.timer = time.AfterFunc(resumeAfter, func() {
+		ResumeProcess(pid)
+	})
+	suspended[pid] = entry
+	saveSuspendedLocked()
+
+	return *entry, nil
+}
+
+// ResumeProcess: SIGCONT. Tidak melewati ProtectionPolicy karena tidak berbahaya
+func ResumeProcess(pid int) error {
+	suspendedLock.Lock()
+	if entry, ok := suspended[pid]; ok {
+		entry.timer.Stop()
+		delete(suspended, pid)
+		saveSuspendedLocked()
+	}
+	suspendedLock.Unlock()
+
+	return SignalProcess(pid, syscall.SIGCONT)
+}
+
+// ListSuspended: proses yang di-suspend lewat agent
+func ListSuspended() []SuspendedProcess {
+	suspendedLock.Lock()
+	defer suspendedLock.Unlock()
+
+	list := make([]SuspendedProcess, 0, len(suspended))
+	pruned := false
+	for pid, entry := range suspended {
+		if !ProcessAlive(pid) {
+			entry.timer.Stop()
+			delete(suspended, pid)
+			pruned = true
+			continue
+		}
+		list = append(list, *entry)
+	}
+	if pruned {
+		saveSuspendedLocked()
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Since < list[j].Since
+	})
+	return list
+}
+
+// ReniceProcess: ubah nilai nice (-20..20). Nilai negatif butuh root
+func ReniceProcess(pid, nice int, override bool) error {
+	if nice < -20 || nice > 20 {
+		return fmt.Errorf("nice value must be between -20 and 20")
+	}
+
+	proc, ok := GetProcess(pid)
+	if !ok {
+		return fmt.Errorf("process %d not found", pid)
+	}
+	if perr := CheckKillPolicy(proc, override); perr != nil {
+		return perr
+	}
+
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+}
+
+// SuspendAppGroup / ResumeAppGroup / ReniceAppGroup: berlaku untuk semua proses dalam grup aplikasi
+func SuspendAppGroup(app string, resumeAfter time.Duration, override bool) ([]ProcessActionResult, error) {
+	return forAppGroup(app, func(pid int) error {
+		_, err := SuspendProcess(pid, resumeAfter, override)
+		return err
+	})
+}
+
+func ResumeAppGroup(app string) ([]ProcessActionResult, error) {
+	return forAppGroup(app, ResumeProcess)
+}
+
+func ReniceAppGroup(app string, nice int, override bool) ([]ProcessActionResult, error) {
+	return forAppGroup(app, func(pid int) error {
+		return ReniceProcess(pid, nice, override)
+	})
+}
+
+func forAppGroup(app string, action func(pid int) error) ([]ProcessActionResult, error) {
+	group, ok := FindAppGroup(app)
+	if !ok {
+		return nil, fmt.Errorf("app not found: %s", app)
+	}
+
+	results := make([]ProcessActionResult, 0, len(group.PIDs))
+	for _, pid := range group.PIDs {
+		results = append(results, newActionResult(pid, group.Name, action(pid)))
+	}
+	return results, nil
+}
+
+func newActionResult(pid int, name string, err error) ProcessActionResult {
+	res := ProcessActionResult{PID: pid, Name: name}
+	if err != nil {
+		res.Error = err.Error()
+		var perr *ProtectionError
+		if errors.As(err, &perr) {
+			res.Code = perr.Code
+		}
+	}
+	return res
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "testing"
+
+func TestParsePsDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		{"00:05", 5},
+		{"01:30", 90},
+		{"12:01:30", 12*3600 + 90},
+		{"3-04:05:06", 3*86400 + 4*3600 + 5*60 + 6},
+		{"0:01.50", 1.5}, // format kolom "time" (CPU time)
+		{"125:10.25", 125*60 + 10.25},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parsePsDuration(tt.in); got != tt.want {
+			t.Errorf("parsePsDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
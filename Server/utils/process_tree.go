@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type ProcessNode struct {
+	Process
+	TotalCPU    float64        `json:"total_cpu"`    // CPU proses + semua turunannya
+	TotalMemory uint64         `json:"total_memory"` // RSS proses + semua turunannya
+	Children    []*ProcessNode `json:"children"`
+}
+
+type AppGroup struct {
+	Name      string  `json:"name"`
+	Bundle    string  `json:"bundle,omitempty"` // Kosong jika bukan bagian dari .app
+	Category  string  `json:"category"`
+	Count     int     `json:"count"`
+	PIDs      []int   `json:"pids"`
+	CPU       float64 `json:"cpu"`
+	RAM       float64 `json:"ram"`
+	Memory    uint64  `json:"memory"`
+	MemoryStr string  `json:"memory_str"`
+}
+
+// BuildProcessTree: susun hierarki parent/child dari tabel proses
+func BuildProcessTree() []*ProcessNode {
+	return buildProcessTree(ReadProcessTable())
+}
+
+func buildProcessTree(processes []Process) []*ProcessNode {
+	nodes := make(map[int]*ProcessNode, len(processes))
+	for _, p := range processes {
+		nodes[p.PID] = &ProcessNode{Process: p, Children: []*ProcessNode{}}
+	}
+
+	roots := []*ProcessNode{}
+	for _, p := range processes {
+		node := nodes[p.PID]
+		parent, ok := nodes[p.PPID]
+		if !ok || p.PPID == p.PID {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, root := range roots {
+		sumSubtree(root)
+	}
+	sortNodes(roots)
+
+	return roots
+}
+
+func sumSubtree(node *ProcessNode) {
+	node.TotalCPU = node.CPU
+	node.TotalMemory = node.Memory
+	for _, child := range node.Children {
+		sumSubtree(child)
+		node.TotalCPU += child.TotalCPU
+		node.TotalMemory += child.TotalMemory
+	}
+}
+
+func sortNodes(nodes []*ProcessNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].PID < nodes[j].PID
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
+// GetAppGroups: gabungkan helper ke bundle .app pemiliknya, diurutkan dari CPU tertinggi
+func GetAppGroups() []AppGroup {
+	return groupByApp(ReadProcessTable())
+}
+
+func groupByApp(processes []Process) []AppGroup {
+	groups := map[string]*AppGroup{}
+	order := []string{}
+
+	for _, p := range processes {
+		key := p.App
+		name := strings.TrimSuffix(filepath.Base(p.App), ".app")
+		if key == "" {
+			// Proses di luar bundle dikelompokkan berdasarkan nama
+			key = "name:" + p.Name
+			name = p.Name
+		}
+
+		g, ok := groups[key]
+		if !ok {
+			g = &AppGroup{
+				Name:     name,
+				Bundle:   p.App,
+				Category: p.Category,
+				PIDs:     []int{},
+			}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		g.Count++
+		g.PIDs = append(g.PIDs, p.PID)
+		g.CPU += p.CPU
+		g.RAM += p.RAM
+		g.Memory += p.Memory
+	}
+
+	result := make([]AppGroup, 0, len(order))
+	for _, key := range order {
+		g := groups[key]
+		g.MemoryStr = formatBytes(g.Memory)
+		result = append(result, *g)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CPU > result[j].CPU
+	})
+
+	return result
+}
+
+// FindAppGroup: cari grup berdasarkan nama aplikasi atau path bundle
+func FindAppGroup(app string) (AppGroup, bool) {
+	for _, g := range GetAppGroups() {
+		if strings.EqualFold(g.Name, app) || g.Bundle == app {
+			return g, true
+		}
+	}
+	return AppGroup{}, false
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+// treeShape: PID -> PID anak, untuk membandingkan bentuk pohon
+func treeShape(nodes []*ProcessNode, shape map[int][]int) {
+	for _, n := range nodes {
+		children := []int{}
+		for _, c := range n.Children {
+			children = append(children, c.PID)
+		}
+		shape[n.PID] = children
+		treeShape(n.Children, shape)
+	}
+}
+
+func TestBuildProcessTree(t *testing.T) {
+	processes := []Process{
+		{PID: 1, PPID: 0, Name: "launchd", CPU: 1, Memory: 100},
+		{PID: 50, PPID: 1, Name: "zsh", CPU: 0.5, Memory: 10},
+		{PID: 20, PPID: 1, Name: "Finder", CPU: 2, Memory: 200},
+		{PID: 60, PPID: 50, Name: "go", CPU: 30, Memory: 500},
+		{PID: 61, PPID: 60, Name: "compile", CPU: 70, Memory: 1000},
+		{PID: 99, PPID: 4242, Name: "orphan", CPU: 3, Memory: 30}, // Parent sudah tidak ada
+		{PID: 0, PPID: 0, Name: "kernel_task", CPU: 5, Memory: 50},
+	}
+
+	roots := buildProcessTree(processes)
+
+	rootPIDs := []int{}
+	for _, r := range roots {
+		rootPIDs = append(rootPIDs, r.PID)
+	}
+	// launchd (PPID 0) berada di bawah kernel_task, sama seperti di macOS
+	if want := []int{0, 99}; !reflect.DeepEqual(rootPIDs, want) {
+		t.Fatalf("roots = %v, want %v", rootPIDs, want)
+	}
+
+	shape := map[int][]int{}
+	treeShape(roots, shape)
+	wantShape := map[int][]int{
+		0:  {1},
+		1:  {20, 50},
+		20: {},
+		50: {60},
+		60: {61},
+		61: {},
+		99: {},
+	}
+	if !reflect.DeepEqual(shape, wantShape) {
+		t.Errorf("tree = %v, want %v", shape, wantShape)
+	}
+
+	launchd := roots[0].Children[0]
+	if launchd.TotalCPU != 103.5 || launchd.TotalMemory != 1810 {
+		t.Errorf("launchd totals = %v CPU / %d bytes, want 103.5 / 1810", launchd.TotalCPU, launchd.TotalMemory)
+	}
+}
+
+func TestGroupByApp(t *testing.T) {
+	chrome := "/Applications/Google Chrome.app"
+	processes := []Process{
+		{PID: 10, Name: "Google Chrome", App: chrome, Category: CategoryBrowser, CPU: 5, RAM: 1, Memory: 100},
+		{PID: 11, Name: "Google Chrome Helper (Renderer)", App: chrome, Category: CategoryBackground, CPU: 15, RAM: 2, Memory: 300},
+		{PID: 20, Name: "node", CPU: 1, Memory: 50},
+		{PID: 21, Name: "node", CPU: 2, Memory: 70},
+		{PID: 30, Name: "WindowServer", CPU: 40, Memory: 900},
+	}
+
+	got := groupByApp(processes)
+	want := []AppGroup{
+		{Name: "WindowServer", PIDs: []int{30}, Count: 1, CPU: 40, Memory: 900, MemoryStr: formatBytes(900)},
+		{Name: "Google Chrome", Bundle: chrome, Category: CategoryBrowser, PIDs: []int{10, 11}, Count: 2, CPU: 20, RAM: 3, Memory: 400, MemoryStr: formatBytes(400)},
+		{Name: "node", PIDs: []int{20, 21}, Count: 2, CPU: 3, Memory: 120, MemoryStr: formatBytes(120)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByApp() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestAppBundleOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/Applications/Safari.app/Contents/MacOS/Safari", "/Applications/Safari.app"},
+		{
+			"/Applications/Google Chrome.app/Contents/Frameworks/Google Chrome Framework.framework/Helpers/Google Chrome Helper.app/Contents/MacOS/Google Chrome Helper",
+			"/Applications/Google Chrome.app",
+		},
+		{"/usr/sbin/syslogd", ""},
+		{"/Applications/Foo.application-support/bin/foo", ""},
+	}
+
+	for _, tt := range tests {
+		if got := appBundleOf(tt.path); got != tt.want {
+			t.Errorf("appBundleOf(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
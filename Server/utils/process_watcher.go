@@ -0,0 +1,268 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TopicProcess = "process"
+
+	watchInterval = 2 * time.Second
+	crashWait     = 5 * time.Second  // Crash report biasanya ditulis beberapa detik setelah proses mati
+	crashGiveUp   = 30 * time.Second // Setelah ini exit dianggap bukan crash
+)
+
+type ProcessEvent struct {
+	PID         int    `json:"pid"`
+	PPID        int    `json:"ppid"`
+	Name        string `json:"name"`
+	Path        string `json:"path,omitempty"`
+	User        string `json:"user,omitempty"`
+	App         string `json:"app,omitempty"`
+	Runtime     int64  `json:"runtime,omitempty"` // Detik, hanya untuk exit
+	CrashReport string `json:"crash_report,omitempty"`
+}
+
+// exitHook: proses dicatat saat registrasi, supaya PID yang dipakai ulang tidak ikut memicu hook
+type exitHook struct {
+	proc Process
+	fn   func(ProcessEvent)
+}
+
+type pendingCrash struct {
+	ev      ProcessEvent
+	started int64
+	exited  time.Time
+}
+
+var (
+	exitHooks     = map[int][]exitHook{}
+	exitHooksLock sync.Mutex
+
+	// Exit yang menunggu crash report, hanya diakses goroutine watcher
+	pendingCrashes []pendingCrash
+
+	// PID di crash report: "pid" : 123 (.ips) atau "Process: Foo [123]" (.crash)
+	crashPIDRe = regexp.MustCompile(`(?:"pid"\s*:\s*|Process:\s+.*\[)(\d+)`)
+)
+
+// StartProcessWatcher: bandingkan snapshot proses berkala dan publish event start/exit/crash
+func StartProcessWatcher() {
+	go func() {
+		prev := snapshotProcesses()
+		for {
+			time.Sleep(watchInterval)
+			cur := snapshotProcesses()
+			diffSnapshots(prev, cur)
+			fireExitHooks(cur)
+			checkCrashReports()
+			prev = cur
+		}
+	}()
+}
+
+func snapshotProcesses() map[int]Process {
+	snap := map[int]Process{}
+	for _, p := range ReadProcessTable() {
+		snap[p.PID] = p
+	}
+	return snap
+}
+
+func diffSnapshots(prev, cur map[int]Process) {
+	if len(cur) == 0 {
+		// ps gagal, jangan anggap semua proses keluar
+		return
+	}
+
+	for pid, p := range cur {
+		old, ok := prev[pid]
+		// Started dihitung dari etime, jadi bisa bergeser satu-dua detik antar snapshot
+		if ok && old.Name == p.Name && abs64(old.Started-p.Started) <= 2 {
+			continue
+		}
+		if ok {
+			// PID dipakai ulang oleh proses lain
+			processExited(old)
+		}
+		PublishEvent(TopicProcess, "start", newProcessEvent(p))
+	}
+
+	for pid, p := range prev {
+		if _, ok := cur[pid]; !ok {
+			processExited(p)
+		}
+	}
+}
+
+func processExited(p Process) {
+	ev := newProcessEvent(p)
+	ev.Runtime = time.Now().Unix() - p.Started
+	PublishEvent(TopicProcess, "exit", ev)
+
+	pendingCrashes = append(pendingCrashes, pendingCrash{ev: ev, started: p.Started, exited: time.Now()})
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func newProcessEvent(p Process) ProcessEvent {
+	return ProcessEvent{
+		PID:  p.PID,
+		PPID: p.PPID,
+		Name: p.Name,
+		Path: p.Path,
+		User: p.User,
+		App:  p.App,
+	}
+}
+
+// checkCrashReports: satu kali scan DiagnosticReports untuk semua exit yang menunggu,
+// report dicocokkan dengan nama dan PID di dalam file
+func checkCrashReports() {
+	if len(pendingCrashes) == 0 {
+		return
+	}
+
+	now := time.Now()
+	due := false
+	for _, pc := range pendingCrashes {
+		if now.Sub(pc.exited) >= crashWait {
+			due = true
+			break
+		}
+	}
+	if !due {
+		return
+	}
+
+	home, _ := os.UserHomeDir()
+	dirs := []string{
+		filepath.Join(home, "Library/Logs/DiagnosticReports"),
+		"/Library/Logs/DiagnosticReports",
+	}
+
+	found := map[int]string{}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if !strings.HasSuffix(e.Name(), ".ips") && !strings.HasSuffix(e.Name(), ".crash") {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			for _, pc := range pendingCrashes {
+				if _, ok := found[pc.ev.PID]; ok || !strings.HasPrefix(e.Name(), pc.ev.Name+"-") || info.ModTime().Unix() < pc.started {
+					continue
+				}
+				path := filepath.Join(dir, e.Name())
+				if crashReportPID(path) == pc.ev.PID {
+					found[pc.ev.PID] = path
+				}
+			}
+		}
+	}
+
+	remaining := pendingCrashes[:0]
+	for _, pc := range pendingCrashes {
+		if report, ok := found[pc.ev.PID]; ok {
+			pc.ev.CrashReport = report
+			PublishEvent(TopicProcess, "crash", pc.ev)
+			continue
+		}
+		if now.Sub(pc.exited) < crashGiveUp {
+			remaining = append(remaining, pc)
+		}
+	}
+	pendingCrashes = remaining
+}
+
+// crashReportPID: PID pertama di awal crash report, 0 jika tidak ketemu
+func crashReportPID(path string) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	head, _ := io.ReadAll(io.LimitReader(f, 64<<10))
+	m := crashPIDRe.FindSubmatch(head)
+	if m == nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(string(m[1]))
+	return pid
+}
+
+// OnProcessExit: panggil fn sekali saat PID keluar (dipakai subsistem lain)
+func OnProcessExit(pid int, fn func(ProcessEvent)) error {
+	proc, ok := GetProcess(pid)
+	if !ok {
+		return fmt.Errorf("process %d not found", pid)
+	}
+
+	exitHooksLock.Lock()
+	exitHooks[pid] = append(exitHooks[pid], exitHook{proc: proc, fn: fn})
+	exitHooksLock.Unlock()
+	return nil
+}
+
+// fireExitHooks: hook untuk PID yang tidak ada lagi di snapshot, atau sudah dipakai proses lain.
+// Tidak bergantung pada snapshot sebelumnya, jadi proses yang keluar di antara dua snapshot tetap terdeteksi.
+func fireExitHooks(cur map[int]Process) {
+	if len(cur) == 0 {
+		return
+	}
+
+	exitHooksLock.Lock()
+	var fired []exitHook
+	for pid, hooks := range exitHooks {
+		remaining := hooks[:0]
+		for _, h := range hooks {
+			if p, ok := cur[pid]; ok && abs64(p.Started-h.proc.Started) <= 2 {
+				remaining = append(remaining, h)
+				continue
+			}
+			fired = append(fired, h)
+		}
+		if len(remaining) == 0 {
+			delete(exitHooks, pid)
+		} else {
+			exitHooks[pid] = remaining
+		}
+	}
+	exitHooksLock.Unlock()
+
+	now := time.Now().Unix()
+	for _, h := range fired {
+		ev := newProcessEvent(h.proc)
+		ev.Runtime = now - h.proc.Started
+		go h.fn(ev)
+	}
+}
+
+// NotifyOnExit: notifikasi macOS saat proses selesai, mis. build yang lama
+func NotifyOnExit(pid int) error {
+	return OnProcessExit(pid, func(ev ProcessEvent) {
+		title := fmt.Sprintf("%s finished", ev.Name)
+		body := fmt.Sprintf("PID %d ran for %s", ev.PID, FormatDuration(ev.Runtime))
+		runOsa(fmt.Sprintf(`display notification %q with title %q`, body, title))
+	})
+}
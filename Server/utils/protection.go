@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+)
+
+// Kode error yang dikirim ke client saat kill ditolak
+const (
+	ProtectCritical  = "protected_critical"
+	ProtectOtherUser = "protected_other_user"
+	ProtectSelf      = "protected_self"
+	ProtectDenied    = "protected_denied"
+)
+
+// Proses yang jika dimatikan membuat sesi logout/hang
+var criticalProcesses = []string{
+	"kernel_task", "launchd", "WindowServer", "loginwindow",
+	"opendirectoryd", "securityd", "configd", "coreservicesd",
+	"logd", "UserEventAgent", "powerd", "diskarbitrationd",
+}
+
+type ProtectionPolicy struct {
+	Allow           []string `json:"allow"`    // Selalu boleh (kecuali agent sendiri)
+	Deny            []string `json:"deny"`     // Selalu ditolak, tidak bisa di-override
+	Critical        []string `json:"critical"` // Tambahan untuk daftar critical bawaan
+	AllowOtherUsers bool     `json:"allow_other_users"`
+}
+
+type ProtectionError struct {
+	PID         int    `json:"pid"`
+	Name        string `json:"name"`
+	Code        string `json:"code"`
+	Message     string `json:"error"`
+	Overridable bool   `json:"overridable"`
+}
+
+func (e *ProtectionError) Error() string {
+	return e.Message
+}
+
+var (
+	policy     ProtectionPolicy
+	policyLock sync.RWMutex
+)
+
+// LoadProtectionPolicy: baca allow/deny list dari file JSON. File tidak ada = policy default
+func LoadProtectionPolicy(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var p ProtectionPolicy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("invalid protection policy %s: %v", path, err)
+	}
+
+	SetProtectionPolicy(p)
+	return nil
+}
+
+func SetProtectionPolicy(p ProtectionPolicy) {
+	policyLock.Lock()
+	defer policyLock.Unlock()
+	policy = p
+}
+
+// GetProtectionPolicy: policy aktif, daftar critical sudah digabung dengan bawaan
+func GetProtectionPolicy() ProtectionPolicy {
+	policyLock.RLock()
+	defer policyLock.RUnlock()
+
+	p := policy
+	p.Critical = append(append([]string{}, criticalProcesses...), policy.Critical...)
+	return p
+}
+
+// CurrentUser: user yang login (SUDO_USER jika agent dijalankan lewat sudo)
+func CurrentUser() string {
+	if u := os.Getenv("SUDO_USER"); u != "" {
+		return u
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return ""
+}
+
+func containsName(list []string, name string) bool {
+	for _, n := range list {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckKillPolicy: nil jika proses boleh dikirimi signal
+func CheckKillPolicy(proc Process, override bool) *ProtectionError {
+	p := GetProtectionPolicy()
+
+	deny := func(code, msg string, overridable bool) *ProtectionError {
+		return &ProtectionError{
+			PID:         proc.PID,
+			Name:        proc.Name,
+			Code:        code,
+			Message:     msg,
+			Overridable: overridable,
+		}
+	}
+
+	if proc.PID == os.Getpid() || proc.PID == os.Getppid() {
+		return deny(ProtectSelf, "refusing to signal the agent or its parent", false)
+	}
+	if containsName(p.Deny, proc.Name) {
+		return deny(ProtectDenied, fmt.Sprintf("%s is on the deny list", proc.Name), false)
+	}
+	if containsName(p.Allow, proc.Name) || override {
+		return nil
+	}
+	if proc.PID <= 1 || containsName(p.Critical, proc.Name) {
+		return deny(ProtectCritical, fmt.Sprintf("%s is a critical system process", proc.Name), true)
+	}
+	if !p.AllowOtherUsers && proc.User != "" && proc.User != CurrentUser() {
+		return deny(ProtectOtherUser, fmt.Sprintf("%s is owned by %s", proc.Name, proc.User), true)
+	}
+
+	return nil
+}
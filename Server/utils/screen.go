@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultJPEGQuality = 70
+
+	minStreamQuality = 20
+	minStreamWidth   = 320
+)
+
+type ScreenshotOptions struct {
+	Display int // 1 = display utama, urutan sama dengan screencapture -D
+	Window  int // CGWindowID dari ListWindows, mengalahkan Display
+	Width   int // Sisi terpanjang dalam pixel, 0 = ukuran asli
+	Quality int // JPEG 1-100
+}
+
+type Window struct {
+	ID       int    `json:"id"`
+	PID      int    `json:"pid"`
+	App      string `json:"app"`
+	Title    string `json:"title"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	OnScreen bool   `json:"on_screen"`
+}
+
+// CaptureScreen: screenshot display/window lewat screencapture, lalu diperkecil dan dikompres ke JPEG dengan sips
+func CaptureScreen(opts ScreenshotOptions) ([]byte, error) {
+	if opts.Quality == 0 {
+		opts.Quality = DefaultJPEGQuality
+	}
+	if opts.Quality < 1 || opts.Quality > 100 {
+		return nil, fmt.Errorf("%w: quality must be between 1 and 100", ErrInvalidValue)
+	}
+	if opts.Width < 0 || opts.Display < 0 || opts.Window < 0 {
+		return nil, fmt.Errorf("%w: display, window and width must not be negative", ErrInvalidValue)
+	}
+
+	base := filepath.Join(os.TempDir(), fmt.Sprintf("agent-screen-%d", time.Now().UnixNano()))
+	png, jpg := base+".png", base+".jpg"
+	defer os.Remove(png)
+	defer os.Remove(jpg)
+
+	// -x: tanpa suara, -o: tanpa bayangan window
+	args := []string{"-x", "-t", "png"}
+	switch {
+	case opts.Window > 0:
+		args = append(args, "-o", "-l", strconv.Itoa(opts.Window))
+	case opts.Display > 0:
+		args = append(args, "-D", strconv.Itoa(opts.Display))
+	}
+	if out, err := exec.Command("screencapture", append(args, png)...).CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		// Display/window yang tidak ada = input salah; selain itu (izin Screen Recording, dll) error runtime
+		if (opts.Display > 0 || opts.Window > 0) && strings.Contains(strings.ToLower(msg), "invalid") {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidValue, msg)
+		}
+		return nil, fmt.Errorf("screencapture failed: %v %s", err, msg)
+	}
+
+	sipsArgs := []string{"-s", "format", "jpeg", "-s", "formatOptions", strconv.Itoa(opts.Quality)}
+	if opts.Width > 0 {
+		sipsArgs = append(sipsArgs, "-Z", strconv.Itoa(opts.Width))
+	}
+	sipsArgs = append(sipsArgs, png, "--out", jpg)
+	if out, err := exec.Command("sips", sipsArgs...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("sips error: %s", strings.TrimSpace(string(out)))
+	}
+
+	return os.ReadFile(jpg)
+}
+
+// ListWindows: window aplikasi (layer 0) yang bisa dipakai untuk CaptureScreen
+func ListWindows() ([]Window, error) {
+	out, err := runJXA(`
+		ObjC.import("CoreGraphics");
+		const info = ObjC.castRefToObject($.CGWindowListCopyWindowInfo(
+			$.kCGWindowListOptionOnScreenOnly | $.kCGWindowListExcludeDesktopElements, $.kCGNullWindowID));
+		JSON.stringify((ObjC.deepUnwrap(info) || [])
+			.filter(w => w.kCGWindowLayer === 0)
+			.map(w => ({
+				id: w.kCGWindowNumber,
+				pid: w.kCGWindowOwnerPID,
+				app: w.kCGWindowOwnerName || "",
+				title: w.kCGWindowName || "",
+				width: w.kCGWindowBounds.Width,
+				height: w.kCGWindowBounds.Height,
+				on_screen: !!w.kCGWindowIsOnscreen,
+			})));
+	`)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := []Window{}
+	if err := json.Unmarshal([]byte(out), &windows); err != nil {
+		return nil, fmt.Errorf("invalid window list: %v", err)
+	}
+	return windows, nil
+}
+
+// AdaptiveQuality: turunkan kualitas (lalu resolusi) jika client lambat menerima frame, naikkan lagi jika lancar
+type AdaptiveQuality struct {
+	Quality    int
+	Width      int
+	MaxQuality int
+	MaxWidth   int
+}
+
+func NewAdaptiveQuality(quality, width int) *AdaptiveQuality {
+	return &AdaptiveQuality{Quality: quality, Width: width, MaxQuality: quality, MaxWidth: width}
+}
+
+// Adjust: sendTime = waktu menulis satu frame ke client, interval = jeda antar frame
+func (a *AdaptiveQuality) Adjust(sendTime, interval time.Duration) {
+	switch {
+	case sendTime > interval/2:
+		if a.Quality > minStreamQuality {
+			a.Quality = max(a.Quality-10, minStreamQuality)
+		} else if a.Width > minStreamWidth {
+			a.Width = max(a.Width*3/4, minStreamWidth)
+		}
+
+	case sendTime < interval/5:
+		if a.Width < a.MaxWidth {
+			a.Width = min(a.Width*4/3, a.MaxWidth)
+		} else if a.Quality < a.MaxQuality {
+			a.Quality = min(a.Quality+5, a.MaxQuality)
+		}
+	}
+}
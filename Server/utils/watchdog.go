@@ -0,0 +1,239 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TopicWatchdog = "watchdog"
+
+	watchdogInterval = 5 * time.Second
+	backoffMin       = 2 * time.Second
+	backoffMax       = 5 * time.Minute
+	stableAfter      = time.Minute      // Backoff di-reset jika app bertahan selama ini
+	launchGrace      = 30 * time.Second // Waktu tunggu app lambat (mis. OBS) muncul sebelum dicoba lagi
+)
+
+type WatchdogApp struct {
+	ID          string `json:"id"`
+	Name        string `json:"name,omitempty"`
+	BundleID    string `json:"bundle_id,omitempty"`
+	Running     bool   `json:"running"`
+	PID         int    `json:"pid,omitempty"`
+	Restarts    int    `json:"restarts"`
+	LastRestart int64  `json:"last_restart,omitempty"` // Unix milidetik
+	NextAttempt int64  `json:"next_attempt,omitempty"` // Unix milidetik, selama backoff
+	LastError   string `json:"last_error,omitempty"`
+
+	backoff time.Duration
+	upSince time.Time
+	wasUp   bool // Pernah terlihat jalan sejak launch terakhir, hanya itu yang dihitung restart
+}
+
+var (
+	watchdogApps = map[string]*WatchdogApp{}
+	watchdogFile string
+	watchdogLock sync.Mutex
+)
+
+// StartWatchdog: muat daftar app dari file lalu cek berkala
+func StartWatchdog(path string) error {
+	watchdogFile = path
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		var apps []WatchdogApp
+		if err := json.Unmarshal(data, &apps); err != nil {
+			return fmt.Errorf("invalid watchdog file %s: %v", path, err)
+		}
+		for _, a := range apps {
+			watchdogApps[a.ID] = &WatchdogApp{ID: a.ID, Name: a.Name, BundleID: a.BundleID, Restarts: a.Restarts}
+		}
+	}
+
+	go func() {
+		for {
+			checkWatchdog()
+			time.Sleep(watchdogInterval)
+		}
+	}()
+	return nil
+}
+
+// RegisterWatchdog: pantau app berdasarkan nama atau bundle ID
+func RegisterWatchdog(name, bundleID string) (WatchdogApp, error) {
+	id := bundleID
+	if id == "" {
+		id = name
+	}
+	if id == "" {
+		return WatchdogApp{}, fmt.Errorf("name or bundle_id is required")
+	}
+
+	watchdogLock.Lock()
+	defer watchdogLock.Unlock()
+
+	app, ok := watchdogApps[id]
+	if !ok {
+		app = &WatchdogApp{ID: id, Name: name, BundleID: bundleID}
+		watchdogApps[id] = app
+	}
+	saveWatchdogLocked()
+
+	return *app, nil
+}
+
+func UnregisterWatchdog(id string) error {
+	watchdogLock.Lock()
+	defer watchdogLock.Unlock()
+
+	if _, ok := watchdogApps[id]; !ok {
+		return fmt.Errorf("watchdog app not found: %s", id)
+	}
+	delete(watchdogApps, id)
+	saveWatchdogLocked()
+	return nil
+}
+
+func ListWatchdog() []WatchdogApp {
+	watchdogLock.Lock()
+	defer watchdogLock.Unlock()
+
+	list := make([]WatchdogApp, 0, len(watchdogApps))
+	for _, a := range watchdogApps {
+		list = append(list, *a)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+func checkWatchdog() {
+	watchdogLock.Lock()
+	empty := len(watchdogApps) == 0
+	watchdogLock.Unlock()
+	if empty {
+		return
+	}
+
+	processes := ReadProcessTable()
+	now := time.Now()
+
+	watchdogLock.Lock()
+	var launch []*WatchdogApp
+	for _, app := range watchdogApps {
+		pid := findWatchedPID(app, processes)
+		if pid > 0 {
+			if !app.Running {
+				app.upSince = now
+			}
+			app.Running = true
+			app.PID = pid
+			app.NextAttempt = 0
+			app.wasUp = true
+			if app.backoff > 0 && now.Sub(app.upSince) >= stableAfter {
+				app.backoff = 0
+			}
+			continue
+		}
+
+		if app.Running {
+			PublishEvent(TopicWatchdog, "down", *app)
+		}
+		app.Running = false
+		app.PID = 0
+
+		if app.NextAttempt > now.UnixMilli() {
+			continue
+		}
+		launch = append(launch, app)
+	}
+	watchdogLock.Unlock()
+
+	// "open" bisa lama, jangan tahan lock selama launch
+	for _, app := range launch {
+		err := launchWatched(app)
+
+		watchdogLock.Lock()
+		if watchdogApps[app.ID] != app {
+			// Sudah di-unregister selama launch
+			watchdogLock.Unlock()
+			continue
+		}
+
+		if app.wasUp {
+			app.Restarts++
+			app.wasUp = false
+		}
+		app.LastRestart = now.UnixMilli()
+		app.LastError = ""
+		if err != nil {
+			app.LastError = err.Error()
+		}
+
+		if app.backoff == 0 {
+			app.backoff = backoffMin
+		} else {
+			app.backoff = min(app.backoff*2, backoffMax)
+		}
+		app.NextAttempt = now.Add(max(app.backoff, launchGrace)).UnixMilli()
+
+		PublishEvent(TopicWatchdog, "relaunch", *app)
+		saveWatchdogLocked()
+		watchdogLock.Unlock()
+	}
+}
+
+// findWatchedPID: proses utama app (bukan helper) berdasarkan bundle ID atau nama
+func findWatchedPID(app *WatchdogApp, processes []Process) int {
+	for _, p := range processes {
+		if app.BundleID != "" {
+			if p.BundleID == app.BundleID && isMainAppProcess(p) {
+				return p.PID
+			}
+			continue
+		}
+		// Helper/crash handler di dalam bundle tidak dihitung sebagai app yang jalan
+		appName := strings.TrimSuffix(filepath.Base(p.App), ".app")
+		if p.App != "" && !isMainAppProcess(p) {
+			continue
+		}
+		if strings.EqualFold(p.Name, app.Name) || (appName != "" && strings.EqualFold(appName, app.Name)) {
+			return p.PID
+		}
+	}
+	return 0
+}
+
+func launchWatched(app *WatchdogApp) error {
+	if app.BundleID != "" {
+		return OpenBundle(app.BundleID)
+	}
+	return OpenApp(app.Name)
+}
+
+func saveWatchdogLocked() {
+	if watchdogFile == "" {
+		return
+	}
+
+	list := make([]WatchdogApp, 0, len(watchdogApps))
+	for _, a := range watchdogApps {
+		list = append(list, *a)
+	}
+	data, _ := json.MarshalIndent(list, "", "  ")
+	if err := os.WriteFile(watchdogFile, data, 0644); err != nil {
+		fmt.Printf("❌ GAGAL simpan watchdog: %v\n", err)
+	}
+}